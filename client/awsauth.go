@@ -12,17 +12,23 @@ import (
 )
 
 type AWS4Authenticator struct {
-	signer  *v4.Signer
-	region  string
-	service string
+	signer          *v4.Signer
+	region          string
+	service         string
+	unsignedPayload bool
 }
 
 func (aws4Auth AWS4Authenticator) Authenticate(req *http.Request) error {
 	var body io.ReadSeeker
-	if req.Body != nil {
+	signer := aws4Auth.signer
+	if req.Body != nil && req.Body != http.NoBody {
 		var canSeek bool
-		if body, canSeek = req.Body.(io.ReadSeeker); !canSeek {
-			return fmt.Errorf("body must be seekable for AWS4 auth")
+		if body, canSeek = req.Body.(io.ReadSeeker); !canSeek || aws4Auth.unsignedPayload {
+			// a streamed body cannot be hashed without reading it, so it is left out of the signature
+			body = nil
+			unsignedSigner := *aws4Auth.signer
+			unsignedSigner.UnsignedPayload = true
+			signer = &unsignedSigner
 		}
 	}
 	if body != nil {
@@ -31,7 +37,7 @@ func (aws4Auth AWS4Authenticator) Authenticate(req *http.Request) error {
 			return fmt.Errorf("failed to seek to start of request body for AWS auth: %v", err)
 		}
 	}
-	header, err := aws4Auth.signer.Sign(req, body, aws4Auth.service, aws4Auth.region, time.Now())
+	header, err := signer.Sign(req, body, aws4Auth.service, aws4Auth.region, time.Now())
 	for key, values := range header {
 		for _, value := range values {
 			req.Header.Set(key, value)
@@ -46,6 +52,20 @@ func (aws4Auth AWS4Authenticator) Authenticate(req *http.Request) error {
 	return err
 }
 
+// WithService returns a copy of the authenticator that signs requests for the given service instead of execute-api,
+// e.g. lambda for Lambda function URLs
+func (aws4Auth AWS4Authenticator) WithService(service string) AWS4Authenticator {
+	aws4Auth.service = service
+	return aws4Auth
+}
+
+// WithUnsignedPayload returns a copy of the authenticator that signs requests with UNSIGNED-PAYLOAD instead of a hash
+// of the body. Bodies that are not seekable are always signed this way. Only some services accept it.
+func (aws4Auth AWS4Authenticator) WithUnsignedPayload(unsignedPayload bool) AWS4Authenticator {
+	aws4Auth.unsignedPayload = unsignedPayload
+	return aws4Auth
+}
+
 func newAWS4Authenticator(creds *credentials.Credentials, region string) AWS4Authenticator {
 	if len(region) == 0 {
 		region = os.Getenv("AWS_REGION")
@@ -54,7 +74,10 @@ func newAWS4Authenticator(creds *credentials.Credentials, region string) AWS4Aut
 		}
 	}
 	return AWS4Authenticator{
-		signer:  v4.NewSigner(creds),
+		signer: v4.NewSigner(creds, func(signer *v4.Signer) {
+			// the request keeps its own body, as streamed bodies are signed without being passed to the signer
+			signer.DisableRequestBodyOverwrite = true
+		}),
 		region:  region,
 		service: "execute-api",
 	}
@@ -0,0 +1,55 @@
+package mcmaclient
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestAWS4AuthSignsStreamedBodyWithUnsignedPayload(t *testing.T) {
+	authenticator := NewAWS4AuthenticatorFromKeys("access-key", "secret-key", "", "eu-west-1").WithService("lambda")
+
+	req, _ := http.NewRequest("POST", "https://abc.lambda-url.eu-west-1.on.aws/jobs", io.NopCloser(strings.NewReader("streamed body")))
+	if err := authenticator.Authenticate(req); err != nil {
+		t.Fatalf("%v", err)
+	}
+	if hash := req.Header.Get("X-Amz-Content-Sha256"); hash != "UNSIGNED-PAYLOAD" {
+		t.Errorf("expected UNSIGNED-PAYLOAD, got %q", hash)
+	}
+	if authorization := req.Header.Get("Authorization"); !strings.Contains(authorization, "/eu-west-1/lambda/aws4_request") {
+		t.Errorf("expected a signature for the lambda service, got %q", authorization)
+	}
+	body, _ := io.ReadAll(req.Body)
+	if string(body) != "streamed body" {
+		t.Errorf("expected the body to be left intact, got %q", body)
+	}
+}
+
+func TestAWS4AuthSignsSeekableBody(t *testing.T) {
+	authenticator := NewAWS4AuthenticatorFromKeys("access-key", "secret-key", "", "eu-west-1")
+
+	req, _ := newHttpRequest("POST", "https://api.example.com/jobs", bytes.NewReader([]byte("body")))
+	if err := authenticator.Authenticate(req); err != nil {
+		t.Fatalf("%v", err)
+	}
+	if hash := req.Header.Get("X-Amz-Content-Sha256"); hash != "" {
+		t.Errorf("expected no content hash header for execute-api, got %q", hash)
+	}
+	if authorization := req.Header.Get("Authorization"); !strings.Contains(authorization, "/eu-west-1/execute-api/aws4_request") {
+		t.Errorf("expected a signature for the execute-api service, got %q", authorization)
+	}
+	body, _ := io.ReadAll(req.Body)
+	if string(body) != "body" {
+		t.Errorf("expected the body to be rewound after signing, got %q", body)
+	}
+
+	req, _ = newHttpRequest("POST", "https://api.example.com/jobs", bytes.NewReader([]byte("body")))
+	if err := authenticator.WithUnsignedPayload(true).Authenticate(req); err != nil {
+		t.Fatalf("%v", err)
+	}
+	if hash := req.Header.Get("X-Amz-Content-Sha256"); hash != "UNSIGNED-PAYLOAD" {
+		t.Errorf("expected UNSIGNED-PAYLOAD, got %q", hash)
+	}
+}
@@ -0,0 +1,192 @@
+package mcmaclient
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/credentials/stscreds"
+	"github.com/aws/aws-sdk-go/aws/endpoints"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/sts"
+)
+
+// AWSCredentialsOptions configures how temporary AWS credentials are obtained
+type AWSCredentialsOptions struct {
+	// StsEndpoint overrides the endpoint of the AWS Security Token Service, e.g. for a VPC endpoint
+	StsEndpoint string
+	// ImdsEndpoint overrides the endpoint of the EC2 instance metadata service
+	ImdsEndpoint string
+	// ExpiryWindow is how long before temporary credentials expire that they are refreshed. The default chain only
+	// applies it to web identity credentials, and otherwise uses the defaults of the AWS SDK.
+	ExpiryWindow time.Duration
+	HttpClient   *http.Client
+}
+
+type AWSAssumeRoleOptions struct {
+	AWSCredentialsOptions
+	ExternalId      string
+	RoleSessionName string
+	Duration        time.Duration
+	// SourceCredentials are used to call AssumeRole. The default credential chain is used if they are not set.
+	SourceCredentials *credentials.Credentials
+}
+
+type AWSWebIdentityOptions struct {
+	AWSCredentialsOptions
+	RoleSessionName string
+}
+
+func (opts AWSCredentialsOptions) newSession(region string) (*session.Session, error) {
+	config := aws.NewConfig().WithCredentials(credentials.AnonymousCredentials)
+	if region != "" {
+		config = config.WithRegion(region)
+	}
+	if opts.HttpClient != nil {
+		config = config.WithHTTPClient(opts.HttpClient)
+	}
+	sess, err := session.NewSession(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create AWS session: %v", err)
+	}
+	return sess, nil
+}
+
+func (opts AWSCredentialsOptions) newStsClient(sess *session.Session, creds *credentials.Credentials) *sts.STS {
+	config := aws.NewConfig().WithCredentials(creds)
+	if aws.StringValue(sess.Config.Region) == "" {
+		// STS is global, so any region can be used to sign the call
+		config = config.WithRegion("us-east-1")
+	}
+	if opts.StsEndpoint != "" {
+		config = config.WithEndpoint(opts.StsEndpoint)
+	}
+	return sts.New(sess, config)
+}
+
+// newDefaultChainCredentials resolves credentials the same way the AWS SDK does for a session with the shared config
+// enabled: environment variables, then the profile named by AWS_PROFILE from the shared credentials and config files,
+// including role_arn with source_profile or credential_source, credential_process, SSO and web identity, then a web
+// identity token from the environment, and then the ECS container or EC2 instance role
+func (opts AWSCredentialsOptions) newDefaultChainCredentials(region string) (*credentials.Credentials, error) {
+	// STS is global, so any region can be used to sign calls made to get the credentials
+	if region == "" {
+		region = "us-east-1"
+	}
+	config := aws.NewConfig().WithRegion(region).WithCredentialsChainVerboseErrors(true)
+	if opts.HttpClient != nil {
+		config = config.WithHTTPClient(opts.HttpClient)
+	}
+	if opts.StsEndpoint != "" {
+		config = config.WithEndpointResolver(endpoints.ResolverFunc(func(service, region string, optFns ...func(*endpoints.Options)) (endpoints.ResolvedEndpoint, error) {
+			if service == sts.EndpointsID {
+				return endpoints.ResolvedEndpoint{URL: opts.StsEndpoint, SigningRegion: region}, nil
+			}
+			return endpoints.DefaultResolver().EndpointFor(service, region, optFns...)
+		}))
+	}
+
+	sess, err := session.NewSessionWithOptions(session.Options{
+		Config:            *config,
+		SharedConfigState: session.SharedConfigEnable,
+		EC2IMDSEndpoint:   opts.ImdsEndpoint,
+		CredentialsProviderOptions: &session.CredentialsProviderOptions{
+			WebIdentityRoleProviderOptions: func(provider *stscreds.WebIdentityRoleProvider) {
+				provider.ExpiryWindow = opts.ExpiryWindow
+			},
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve AWS credentials from the default chain: %v", err)
+	}
+	return sess.Config.Credentials, nil
+}
+
+func (opts AWSCredentialsOptions) newWebIdentityProvider(sess *session.Session, roleArn, tokenFile, roleSessionName string) credentials.Provider {
+	stsClient := opts.newStsClient(sess, credentials.AnonymousCredentials)
+	return stscreds.NewWebIdentityRoleProviderWithOptions(stsClient, roleArn, roleSessionName, stscreds.FetchTokenPath(tokenFile), func(provider *stscreds.WebIdentityRoleProvider) {
+		provider.ExpiryWindow = opts.ExpiryWindow
+	})
+}
+
+func NewAWS4AuthenticatorFromCredentials(creds *credentials.Credentials, region string) AWS4Authenticator {
+	return newAWS4Authenticator(creds, region)
+}
+
+// NewAWS4AuthenticatorFromDefaultChain signs requests with the first credentials found in the environment variables,
+// the profile in the shared credentials and config files, a web identity token, or the ECS container or EC2 instance
+// role
+func NewAWS4AuthenticatorFromDefaultChain(region string, opts AWSCredentialsOptions) (AWS4Authenticator, error) {
+	creds, err := opts.newDefaultChainCredentials(region)
+	if err != nil {
+		return AWS4Authenticator{}, err
+	}
+	return newAWS4Authenticator(creds, region), nil
+}
+
+// NewAWS4AuthenticatorFromAssumeRole signs requests with credentials for the given role, which are refreshed before
+// they expire
+func NewAWS4AuthenticatorFromAssumeRole(roleArn, region string, opts AWSAssumeRoleOptions) (AWS4Authenticator, error) {
+	sess, err := opts.newSession(region)
+	if err != nil {
+		return AWS4Authenticator{}, err
+	}
+	sourceCredentials := opts.SourceCredentials
+	if sourceCredentials == nil {
+		if sourceCredentials, err = opts.newDefaultChainCredentials(region); err != nil {
+			return AWS4Authenticator{}, err
+		}
+	}
+	creds := stscreds.NewCredentialsWithClient(opts.newStsClient(sess, sourceCredentials), roleArn, func(provider *stscreds.AssumeRoleProvider) {
+		if opts.ExternalId != "" {
+			provider.ExternalID = aws.String(opts.ExternalId)
+		}
+		provider.RoleSessionName = opts.RoleSessionName
+		if opts.Duration > 0 {
+			provider.Duration = opts.Duration
+		}
+		provider.ExpiryWindow = opts.ExpiryWindow
+	})
+	return newAWS4Authenticator(creds, region), nil
+}
+
+// NewAWS4AuthenticatorFromWebIdentity signs requests with credentials for the given role, obtained by exchanging the
+// web identity token in tokenFile, e.g. a Kubernetes service account token. The token file is read again on every
+// refresh so that rotated tokens are picked up.
+func NewAWS4AuthenticatorFromWebIdentity(roleArn, tokenFile, region string, opts AWSWebIdentityOptions) (AWS4Authenticator, error) {
+	sess, err := opts.newSession(region)
+	if err != nil {
+		return AWS4Authenticator{}, err
+	}
+	creds := credentials.NewCredentials(opts.newWebIdentityProvider(sess, roleArn, tokenFile, opts.RoleSessionName))
+	return newAWS4Authenticator(creds, region), nil
+}
+
+func (resourceManager *ResourceManager) AddAWS4AuthFromDefaultChain(region string, opts AWSCredentialsOptions) error {
+	authenticator, err := NewAWS4AuthenticatorFromDefaultChain(region, opts)
+	if err != nil {
+		return err
+	}
+	resourceManager.AddAWS4Auth(authenticator)
+	return nil
+}
+
+func (resourceManager *ResourceManager) AddAWS4AuthFromAssumeRole(roleArn, region string, opts AWSAssumeRoleOptions) error {
+	authenticator, err := NewAWS4AuthenticatorFromAssumeRole(roleArn, region, opts)
+	if err != nil {
+		return err
+	}
+	resourceManager.AddAWS4Auth(authenticator)
+	return nil
+}
+
+func (resourceManager *ResourceManager) AddAWS4AuthFromWebIdentity(roleArn, tokenFile, region string, opts AWSWebIdentityOptions) error {
+	authenticator, err := NewAWS4AuthenticatorFromWebIdentity(roleArn, tokenFile, region, opts)
+	if err != nil {
+		return err
+	}
+	resourceManager.AddAWS4Auth(authenticator)
+	return nil
+}
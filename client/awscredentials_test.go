@@ -0,0 +1,252 @@
+package mcmaclient
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws/credentials"
+)
+
+type fakeAwsServer struct {
+	*httptest.Server
+	mutex    sync.Mutex
+	requests []map[string]string
+	lifetime time.Duration
+}
+
+func newFakeAwsServer(lifetime time.Duration) *fakeAwsServer {
+	s := &fakeAwsServer{lifetime: lifetime}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	return s
+}
+
+func (s *fakeAwsServer) getRequests() []map[string]string {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.requests
+}
+
+func (s *fakeAwsServer) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	expiration := time.Now().Add(s.lifetime).UTC().Format(time.RFC3339)
+
+	switch {
+	case r.Method == "PUT" && r.URL.Path == "/latest/api/token":
+		_, _ = w.Write([]byte("imds-token"))
+	case r.URL.Path == "/latest/meta-data/iam/security-credentials/":
+		_, _ = w.Write([]byte("instance-role"))
+	case r.URL.Path == "/latest/meta-data/iam/security-credentials/instance-role":
+		writeJson(w, http.StatusOK, map[string]interface{}{
+			"Code":            "Success",
+			"AccessKeyId":     "IMDSACCESSKEY",
+			"SecretAccessKey": "imds-secret",
+			"Token":           "imds-session-token",
+			"Expiration":      expiration,
+		})
+	case r.Method == "POST":
+		_ = r.ParseForm()
+		request := map[string]string{"Authorization": r.Header.Get("Authorization")}
+		for key := range r.PostForm {
+			request[key] = r.PostForm.Get(key)
+		}
+		s.mutex.Lock()
+		s.requests = append(s.requests, request)
+		count := len(s.requests)
+		s.mutex.Unlock()
+
+		action := request["Action"]
+		w.Header().Set("Content-Type", "text/xml")
+		_, _ = fmt.Fprintf(w, `<%[1]sResponse xmlns="https://sts.amazonaws.com/doc/2011-06-15/">
+  <%[1]sResult>
+    <Credentials>
+      <AccessKeyId>STSACCESSKEY%[2]d</AccessKeyId>
+      <SecretAccessKey>sts-secret</SecretAccessKey>
+      <SessionToken>sts-session-token</SessionToken>
+      <Expiration>%[3]s</Expiration>
+    </Credentials>
+  </%[1]sResult>
+  <ResponseMetadata><RequestId>request-%[2]d</RequestId></ResponseMetadata>
+</%[1]sResponse>`, action, count, expiration)
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func signedAccessKey(t *testing.T, authenticator Authenticator) string {
+	req, _ := http.NewRequest("GET", "https://api.example.com/jobs", nil)
+	if err := authenticator.Authenticate(req); err != nil {
+		t.Fatalf("%v", err)
+	}
+	authorization := req.Header.Get("Authorization")
+	start := strings.Index(authorization, "Credential=")
+	if start < 0 {
+		t.Fatalf("unexpected authorization header %q", authorization)
+	}
+	credential := authorization[start+len("Credential="):]
+	return credential[:strings.Index(credential, "/")]
+}
+
+func TestAWS4AuthFromAssumeRole(t *testing.T) {
+	server := newFakeAwsServer(time.Hour)
+	defer server.Close()
+
+	authenticator, err := NewAWS4AuthenticatorFromAssumeRole("arn:aws:iam::123456789012:role/mcma", "eu-west-1", AWSAssumeRoleOptions{
+		AWSCredentialsOptions: AWSCredentialsOptions{StsEndpoint: server.URL},
+		ExternalId:            "external-id",
+		RoleSessionName:       "mcma-session",
+		SourceCredentials:     credentials.NewStaticCredentials("SOURCEKEY", "source-secret", ""),
+	})
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	if accessKey := signedAccessKey(t, authenticator); accessKey != "STSACCESSKEY1" {
+		t.Errorf("expected the assumed role credentials, got %s", accessKey)
+	}
+	if accessKey := signedAccessKey(t, authenticator); accessKey != "STSACCESSKEY1" {
+		t.Errorf("expected the credentials to be cached, got %s", accessKey)
+	}
+
+	requests := server.getRequests()
+	if len(requests) != 1 {
+		t.Fatalf("expected 1 call to STS, got %d", len(requests))
+	}
+	request := requests[0]
+	if request["Action"] != "AssumeRole" || request["RoleArn"] != "arn:aws:iam::123456789012:role/mcma" ||
+		request["ExternalId"] != "external-id" || request["RoleSessionName"] != "mcma-session" {
+		t.Errorf("unexpected AssumeRole request %v", request)
+	}
+	if !strings.Contains(request["Authorization"], "Credential=SOURCEKEY/") {
+		t.Errorf("expected AssumeRole to be signed with the source credentials, got %q", request["Authorization"])
+	}
+}
+
+func TestAWS4AuthRefreshesWithinExpiryWindow(t *testing.T) {
+	server := newFakeAwsServer(time.Minute)
+	defer server.Close()
+
+	authenticator, err := NewAWS4AuthenticatorFromAssumeRole("arn:aws:iam::123456789012:role/mcma", "eu-west-1", AWSAssumeRoleOptions{
+		AWSCredentialsOptions: AWSCredentialsOptions{StsEndpoint: server.URL, ExpiryWindow: 2 * time.Minute},
+		SourceCredentials:     credentials.NewStaticCredentials("SOURCEKEY", "source-secret", ""),
+	})
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	// the credentials expire within the expiry window, so they are refreshed on every use
+	if accessKey := signedAccessKey(t, authenticator); accessKey != "STSACCESSKEY1" {
+		t.Errorf("expected the first credentials, got %s", accessKey)
+	}
+	if accessKey := signedAccessKey(t, authenticator); accessKey != "STSACCESSKEY2" {
+		t.Errorf("expected refreshed credentials, got %s", accessKey)
+	}
+}
+
+func TestAWS4AuthFromWebIdentity(t *testing.T) {
+	server := newFakeAwsServer(time.Hour)
+	defer server.Close()
+
+	tokenFile := filepath.Join(t.TempDir(), "token")
+	if err := os.WriteFile(tokenFile, []byte("web-identity-token"), 0600); err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	authenticator, err := NewAWS4AuthenticatorFromWebIdentity("arn:aws:iam::123456789012:role/mcma", tokenFile, "eu-west-1", AWSWebIdentityOptions{
+		AWSCredentialsOptions: AWSCredentialsOptions{StsEndpoint: server.URL},
+		RoleSessionName:       "mcma-session",
+	})
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	if accessKey := signedAccessKey(t, authenticator); accessKey != "STSACCESSKEY1" {
+		t.Errorf("expected the web identity credentials, got %s", accessKey)
+	}
+
+	request := server.getRequests()[0]
+	if request["Action"] != "AssumeRoleWithWebIdentity" || request["WebIdentityToken"] != "web-identity-token" || request["RoleSessionName"] != "mcma-session" {
+		t.Errorf("unexpected AssumeRoleWithWebIdentity request %v", request)
+	}
+}
+
+func clearAwsEnv(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"AWS_ACCESS_KEY_ID", "AWS_ACCESS_KEY", "AWS_SECRET_ACCESS_KEY", "AWS_SECRET_KEY", "AWS_SESSION_TOKEN",
+		"AWS_PROFILE", "AWS_WEB_IDENTITY_TOKEN_FILE", "AWS_ROLE_ARN", "AWS_CONTAINER_CREDENTIALS_RELATIVE_URI", "AWS_CONTAINER_CREDENTIALS_FULL_URI"} {
+		t.Setenv(name, "")
+	}
+	t.Setenv("AWS_SHARED_CREDENTIALS_FILE", filepath.Join(dir, "credentials"))
+	t.Setenv("AWS_CONFIG_FILE", filepath.Join(dir, "config"))
+}
+
+func TestAWS4AuthFromDefaultChain(t *testing.T) {
+	server := newFakeAwsServer(time.Hour)
+	defer server.Close()
+	clearAwsEnv(t)
+
+	authenticator, err := NewAWS4AuthenticatorFromDefaultChain("eu-west-1", AWSCredentialsOptions{ImdsEndpoint: server.URL})
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	if accessKey := signedAccessKey(t, authenticator); accessKey != "IMDSACCESSKEY" {
+		t.Errorf("expected the instance role credentials, got %s", accessKey)
+	}
+
+	t.Setenv("AWS_ACCESS_KEY_ID", "ENVACCESSKEY")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "env-secret")
+	authenticator, err = NewAWS4AuthenticatorFromDefaultChain("eu-west-1", AWSCredentialsOptions{ImdsEndpoint: server.URL})
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	if accessKey := signedAccessKey(t, authenticator); accessKey != "ENVACCESSKEY" {
+		t.Errorf("expected the environment credentials to take precedence, got %s", accessKey)
+	}
+}
+
+func TestAWS4AuthFromDefaultChainUsesConfigFileProfiles(t *testing.T) {
+	server := newFakeAwsServer(time.Hour)
+	defer server.Close()
+	clearAwsEnv(t)
+
+	config := `[profile base]
+aws_access_key_id = BASEACCESSKEY
+aws_secret_access_key = base-secret
+
+[profile mcma]
+role_arn = arn:aws:iam::123456789012:role/mcma
+source_profile = base
+`
+	if err := os.WriteFile(os.Getenv("AWS_CONFIG_FILE"), []byte(config), 0600); err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	t.Setenv("AWS_PROFILE", "base")
+	authenticator, err := NewAWS4AuthenticatorFromDefaultChain("eu-west-1", AWSCredentialsOptions{ImdsEndpoint: server.URL, StsEndpoint: server.URL})
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	if accessKey := signedAccessKey(t, authenticator); accessKey != "BASEACCESSKEY" {
+		t.Errorf("expected the credentials of the config file profile, got %s", accessKey)
+	}
+
+	t.Setenv("AWS_PROFILE", "mcma")
+	authenticator, err = NewAWS4AuthenticatorFromDefaultChain("eu-west-1", AWSCredentialsOptions{ImdsEndpoint: server.URL, StsEndpoint: server.URL})
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	if accessKey := signedAccessKey(t, authenticator); accessKey != "STSACCESSKEY1" {
+		t.Errorf("expected the credentials of the profile's role, got %s", accessKey)
+	}
+	requests := server.getRequests()
+	if len(requests) != 1 || requests[0]["Action"] != "AssumeRole" || requests[0]["RoleArn"] != "arn:aws:iam::123456789012:role/mcma" {
+		t.Fatalf("expected the profile's role to be assumed, got %v", requests)
+	}
+	if !strings.Contains(requests[0]["Authorization"], "Credential=BASEACCESSKEY/") {
+		t.Errorf("expected AssumeRole to be signed with the source profile's credentials, got %q", requests[0]["Authorization"])
+	}
+}
@@ -0,0 +1,216 @@
+package mcmaclient
+
+import (
+	"context"
+	"crypto/rsa"
+	"crypto/sha1"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+)
+
+const defaultAzureAuthorityHost = "https://login.microsoftonline.com"
+
+const defaultAzureImdsEndpoint = "http://169.254.169.254/metadata/identity/oauth2/token"
+
+type AzureADOptions struct {
+	// AuthorityHost overrides the Azure AD host, e.g. for sovereign clouds
+	AuthorityHost string
+	// ImdsEndpoint overrides the token endpoint of the instance metadata service used for managed identities
+	ImdsEndpoint string
+	// ManagedIdentityClientId selects a user-assigned managed identity. The system-assigned identity is used if empty.
+	ManagedIdentityClientId string
+	// RefreshBefore is how long before a token expires that a new one is fetched in the background. Defaults to a
+	// minute.
+	RefreshBefore time.Duration
+	HttpClient    *http.Client
+}
+
+// AzureADAuthenticator adds a bearer token from Azure AD to requests
+type AzureADAuthenticator struct {
+	cache *tokenCache
+}
+
+func (azureAuth *AzureADAuthenticator) Authenticate(req *http.Request) error {
+	token, err := azureAuth.cache.get(req.Context())
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+token.value)
+	return nil
+}
+
+func newAzureADAuthenticator(opts AzureADOptions, fetch func(ctx context.Context) (*tokenResponseJson, error)) *AzureADAuthenticator {
+	return &AzureADAuthenticator{
+		cache: newTokenCache(opts.RefreshBefore, func(ctx context.Context) (cachedToken, error) {
+			issuedAt := time.Now()
+			tokenResponse, err := fetch(ctx)
+			if err != nil {
+				return cachedToken{}, err
+			}
+			if tokenResponse.AccessToken == "" {
+				return cachedToken{}, errors.New("azure ad token response has no access token")
+			}
+			return cachedToken{
+				value:     tokenResponse.AccessToken,
+				tokenType: tokenResponse.TokenType,
+				expiresAt: tokenResponse.expiresAt(issuedAt),
+			}, nil
+		}),
+	}
+}
+
+func (opts AzureADOptions) getTokenUrl(tenantId string) string {
+	authorityHost := opts.AuthorityHost
+	if authorityHost == "" {
+		authorityHost = defaultAzureAuthorityHost
+	}
+	return strings.TrimSuffix(authorityHost, "/") + "/" + url.PathEscape(tenantId) + "/oauth2/v2.0/token"
+}
+
+// toAzureScope turns an application id uri into the scope for its default permissions
+func toAzureScope(scope string) string {
+	if strings.HasSuffix(scope, "/.default") {
+		return scope
+	}
+	return strings.TrimSuffix(scope, "/") + "/.default"
+}
+
+// NewAzureADClientSecretAuthenticator gets tokens for the given scope, e.g. api://mcma-job-processor/.default, from
+// Azure AD with a client secret
+func NewAzureADClientSecretAuthenticator(tenantId, clientId, clientSecret, scope string, opts AzureADOptions) *AzureADAuthenticator {
+	tokenUrl := opts.getTokenUrl(tenantId)
+	return newAzureADAuthenticator(opts, func(ctx context.Context) (*tokenResponseJson, error) {
+		form := url.Values{}
+		form.Set("grant_type", "client_credentials")
+		form.Set("client_id", clientId)
+		form.Set("client_secret", clientSecret)
+		form.Set("scope", toAzureScope(scope))
+		return requestToken(ctx, opts.HttpClient, tokenUrl, form, nil)
+	})
+}
+
+// NewAzureADClientCertificateAuthenticator gets tokens for the given scope from Azure AD with a client assertion
+// signed by the private key of a certificate registered for the application
+func NewAzureADClientCertificateAuthenticator(tenantId, clientId string, certificate *x509.Certificate, privateKey *rsa.PrivateKey, scope string, opts AzureADOptions) *AzureADAuthenticator {
+	tokenUrl := opts.getTokenUrl(tenantId)
+	thumbprint := sha1.Sum(certificate.Raw)
+	return newAzureADAuthenticator(opts, func(ctx context.Context) (*tokenResponseJson, error) {
+		now := time.Now()
+		jti, err := newUuid()
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate client assertion id: %v", err)
+		}
+		assertion, err := signJwtRS256(map[string]interface{}{
+			"x5t": base64.RawURLEncoding.EncodeToString(thumbprint[:]),
+		}, map[string]interface{}{
+			"aud": tokenUrl,
+			"iss": clientId,
+			"sub": clientId,
+			"jti": jti,
+			"iat": now.Unix(),
+			"nbf": now.Unix(),
+			"exp": now.Add(10 * time.Minute).Unix(),
+		}, privateKey)
+		if err != nil {
+			return nil, err
+		}
+
+		form := url.Values{}
+		form.Set("grant_type", "client_credentials")
+		form.Set("client_id", clientId)
+		form.Set("client_assertion_type", "urn:ietf:params:oauth:client-assertion-type:jwt-bearer")
+		form.Set("client_assertion", assertion)
+		form.Set("scope", toAzureScope(scope))
+		return requestToken(ctx, opts.HttpClient, tokenUrl, form, nil)
+	})
+}
+
+// NewAzureADManagedIdentityAuthenticator gets tokens for the given resource, e.g. api://mcma-job-processor, from the
+// managed identity of the App Service, Function App or VM the code runs on. On App Service the IDENTITY_ENDPOINT and
+// IDENTITY_HEADER environment variables are used, and the instance metadata service otherwise.
+func NewAzureADManagedIdentityAuthenticator(resource string, opts AzureADOptions) *AzureADAuthenticator {
+	resource = strings.TrimSuffix(resource, "/.default")
+	return newAzureADAuthenticator(opts, func(ctx context.Context) (*tokenResponseJson, error) {
+		query := url.Values{}
+		query.Set("resource", resource)
+		if opts.ManagedIdentityClientId != "" {
+			query.Set("client_id", opts.ManagedIdentityClientId)
+		}
+
+		var req *http.Request
+		var err error
+		if identityEndpoint, identityHeader := os.Getenv("IDENTITY_ENDPOINT"), os.Getenv("IDENTITY_HEADER"); identityEndpoint != "" && identityHeader != "" {
+			query.Set("api-version", "2019-08-01")
+			req, err = http.NewRequestWithContext(ctx, "GET", identityEndpoint+"?"+query.Encode(), nil)
+			if err != nil {
+				return nil, err
+			}
+			req.Header.Set("X-IDENTITY-HEADER", identityHeader)
+		} else {
+			imdsEndpoint := opts.ImdsEndpoint
+			if imdsEndpoint == "" {
+				imdsEndpoint = defaultAzureImdsEndpoint
+			}
+			query.Set("api-version", "2018-02-01")
+			req, err = http.NewRequestWithContext(ctx, "GET", imdsEndpoint+"?"+query.Encode(), nil)
+			if err != nil {
+				return nil, err
+			}
+			req.Header.Set("Metadata", "true")
+		}
+		return doTokenRequest(opts.HttpClient, req)
+	})
+}
+
+// ParseAzureADCertificate parses the certificate and RSA private key of an application from PEM data, such as a
+// certificate downloaded from Key Vault
+func ParseAzureADCertificate(pemData []byte) (*x509.Certificate, *rsa.PrivateKey, error) {
+	var certificate *x509.Certificate
+	rest := pemData
+	for {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			break
+		}
+		if block.Type == "CERTIFICATE" {
+			var err error
+			if certificate, err = x509.ParseCertificate(block.Bytes); err != nil {
+				return nil, nil, fmt.Errorf("failed to parse certificate: %v", err)
+			}
+			break
+		}
+	}
+	if certificate == nil {
+		return nil, nil, errors.New("no certificate found in pem data")
+	}
+	privateKey, err := parseRsaPrivateKey(pemData)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to parse private key: %v", err)
+	}
+	return certificate, privateKey, nil
+}
+
+func (resourceManager *ResourceManager) AddAzureADAuthFromClientSecret(tenantId, clientId, clientSecret, scope string, opts AzureADOptions) {
+	resourceManager.AddAzureADAuth(NewAzureADClientSecretAuthenticator(tenantId, clientId, clientSecret, scope, opts))
+}
+
+func (resourceManager *ResourceManager) AddAzureADAuthFromClientCertificate(tenantId, clientId string, certificate *x509.Certificate, privateKey *rsa.PrivateKey, scope string, opts AzureADOptions) {
+	resourceManager.AddAzureADAuth(NewAzureADClientCertificateAuthenticator(tenantId, clientId, certificate, privateKey, scope, opts))
+}
+
+func (resourceManager *ResourceManager) AddAzureADAuthFromManagedIdentity(resource string, opts AzureADOptions) {
+	resourceManager.AddAzureADAuth(NewAzureADManagedIdentityAuthenticator(resource, opts))
+}
+
+func (resourceManager *ResourceManager) AddAzureADAuth(authenticator *AzureADAuthenticator) {
+	resourceManager.AddAuth("AzureAD", authenticator)
+}
@@ -0,0 +1,181 @@
+package mcmaclient
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+type fakeAzureServer struct {
+	*httptest.Server
+	mutex    sync.Mutex
+	requests []*http.Request
+	forms    []url.Values
+}
+
+func newFakeAzureServer() *fakeAzureServer {
+	s := &fakeAzureServer{}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = r.ParseForm()
+		s.mutex.Lock()
+		s.requests = append(s.requests, r)
+		s.forms = append(s.forms, r.Form)
+		s.mutex.Unlock()
+
+		switch {
+		case r.Method == "POST" && r.URL.Path == "/tenant-1/oauth2/v2.0/token":
+			writeJson(w, http.StatusOK, map[string]interface{}{"access_token": "aad-token", "token_type": "Bearer", "expires_in": 3599})
+		case r.URL.Path == "/metadata/identity/oauth2/token" && r.Header.Get("Metadata") == "true":
+			writeJson(w, http.StatusOK, map[string]interface{}{"access_token": "imds-token", "token_type": "Bearer", "expires_in": "3599", "expires_on": "0"})
+		case r.URL.Path == "/msi/token" && r.Header.Get("X-IDENTITY-HEADER") == "identity-secret":
+			expiresOn := strconv.FormatInt(time.Now().Add(time.Hour).Unix(), 10)
+			writeJson(w, http.StatusOK, map[string]interface{}{"access_token": "app-service-token", "token_type": "Bearer", "expires_on": expiresOn})
+		default:
+			writeJson(w, http.StatusBadRequest, map[string]interface{}{"error": "invalid_request", "error_description": "unexpected request"})
+		}
+	}))
+	return s
+}
+
+func (s *fakeAzureServer) lastRequest() (*http.Request, url.Values) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.requests[len(s.requests)-1], s.forms[len(s.forms)-1]
+}
+
+func TestAzureADClientSecretAuthenticator(t *testing.T) {
+	server := newFakeAzureServer()
+	defer server.Close()
+
+	authenticator := NewAzureADClientSecretAuthenticator("tenant-1", "client-1", "secret", "api://mcma-service", AzureADOptions{AuthorityHost: server.URL})
+	if authorization := authorizationFor(t, authenticator); authorization != "Bearer aad-token" {
+		t.Errorf("expected the azure ad token, got %q", authorization)
+	}
+	_, form := server.lastRequest()
+	if form.Get("client_id") != "client-1" || form.Get("client_secret") != "secret" || form.Get("scope") != "api://mcma-service/.default" {
+		t.Errorf("unexpected token request %v", form)
+	}
+}
+
+func newTestCertificate(t *testing.T) (*x509.Certificate, *rsa.PrivateKey, []byte) {
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "mcma-test"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &privateKey.PublicKey, privateKey)
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	certificate, _ := x509.ParseCertificate(der)
+	pemData := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	pemData = append(pemData, pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(privateKey)})...)
+	return certificate, privateKey, pemData
+}
+
+func TestAzureADClientCertificateAuthenticator(t *testing.T) {
+	server := newFakeAzureServer()
+	defer server.Close()
+
+	_, _, pemData := newTestCertificate(t)
+	certificate, privateKey, err := ParseAzureADCertificate(pemData)
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	resourceManager := NewResourceManagerWithOptions(server.URL)
+	resourceManager.AddAzureADAuthFromClientCertificate("tenant-1", "client-1", certificate, privateKey, "api://mcma-service/.default", AzureADOptions{AuthorityHost: server.URL})
+	authenticator, err := resourceManager.authProvider.Get("AzureAD")
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	if authorization := authorizationFor(t, authenticator); authorization != "Bearer aad-token" {
+		t.Errorf("expected the azure ad token, got %q", authorization)
+	}
+
+	_, form := server.lastRequest()
+	if form.Get("client_assertion_type") != "urn:ietf:params:oauth:client-assertion-type:jwt-bearer" || form.Get("client_secret") != "" {
+		t.Fatalf("unexpected token request %v", form)
+	}
+
+	parts := strings.Split(form.Get("client_assertion"), ".")
+	if len(parts) != 3 {
+		t.Fatalf("expected a jwt, got %q", form.Get("client_assertion"))
+	}
+	var header, claims map[string]interface{}
+	headerJson, _ := base64.RawURLEncoding.DecodeString(parts[0])
+	claimsJson, _ := base64.RawURLEncoding.DecodeString(parts[1])
+	_ = json.Unmarshal(headerJson, &header)
+	_ = json.Unmarshal(claimsJson, &claims)
+
+	thumbprint := sha1.Sum(certificate.Raw)
+	if header["alg"] != "RS256" || header["x5t"] != base64.RawURLEncoding.EncodeToString(thumbprint[:]) {
+		t.Errorf("unexpected jwt header %v", header)
+	}
+	if claims["aud"] != server.URL+"/tenant-1/oauth2/v2.0/token" || claims["iss"] != "client-1" || claims["sub"] != "client-1" {
+		t.Errorf("unexpected jwt claims %v", claims)
+	}
+
+	signature, _ := base64.RawURLEncoding.DecodeString(parts[2])
+	hash := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+	if err := rsa.VerifyPKCS1v15(certificate.PublicKey.(*rsa.PublicKey), crypto.SHA256, hash[:], signature); err != nil {
+		t.Errorf("expected a valid signature, got %v", err)
+	}
+}
+
+func TestAzureADManagedIdentityAuthenticator(t *testing.T) {
+	server := newFakeAzureServer()
+	defer server.Close()
+	t.Setenv("IDENTITY_ENDPOINT", "")
+	t.Setenv("IDENTITY_HEADER", "")
+
+	resourceManager := NewResourceManagerWithOptions(server.URL)
+	resourceManager.AddAzureADAuthFromManagedIdentity("api://mcma-service", AzureADOptions{
+		ImdsEndpoint:            server.URL + "/metadata/identity/oauth2/token",
+		ManagedIdentityClientId: "identity-1",
+	})
+	authenticator, err := resourceManager.authProvider.Get("AzureAD")
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	if authorization := authorizationFor(t, authenticator); authorization != "Bearer imds-token" {
+		t.Errorf("expected the imds token, got %q", authorization)
+	}
+	req, _ := server.lastRequest()
+	query := req.URL.Query()
+	if query.Get("resource") != "api://mcma-service" || query.Get("client_id") != "identity-1" || query.Get("api-version") != "2018-02-01" {
+		t.Errorf("unexpected imds request %v", query)
+	}
+
+	t.Setenv("IDENTITY_ENDPOINT", server.URL+"/msi/token")
+	t.Setenv("IDENTITY_HEADER", "identity-secret")
+	authenticator = NewAzureADManagedIdentityAuthenticator("api://mcma-service/.default", AzureADOptions{})
+	if authorization := authorizationFor(t, authenticator); authorization != "Bearer app-service-token" {
+		t.Errorf("expected the app service token, got %q", authorization)
+	}
+	req, _ = server.lastRequest()
+	if query := req.URL.Query(); query.Get("resource") != "api://mcma-service" || query.Get("api-version") != "2019-08-01" {
+		t.Errorf("unexpected app service request %v", query)
+	}
+}
@@ -0,0 +1,108 @@
+package mcmaclient
+
+import (
+	"math"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// BackoffStrategy decides how long to wait before each retry
+type BackoffStrategy interface {
+	// NextDelay returns the delay before the given retry, starting at 1, given the delay used before the previous
+	// retry. It returns false when no more retries should be made.
+	NextDelay(retry int, previous time.Duration) (time.Duration, bool)
+}
+
+// FixedBackoff waits for each of the intervals in turn and stops retrying once they are used up
+type FixedBackoff []time.Duration
+
+func (b FixedBackoff) NextDelay(retry int, previous time.Duration) (time.Duration, bool) {
+	if retry < 1 || retry > len(b) {
+		return 0, false
+	}
+	return b[retry-1], true
+}
+
+// ExponentialBackoff multiplies the delay by Multiplier on every retry, starting from Initial and capped at Max. Jitter
+// is the fraction, between 0 and 1, of each delay that is randomized so that clients do not retry in lockstep. It
+// retries until MaxRetries is reached, or indefinitely if MaxRetries is zero, so it should be combined with
+// RetryOptions.MaxAttempts or RetryOptions.MaxElapsedTime in that case.
+type ExponentialBackoff struct {
+	Initial    time.Duration
+	Multiplier float64
+	Max        time.Duration
+	Jitter     float64
+	MaxRetries int
+}
+
+func (b ExponentialBackoff) NextDelay(retry int, previous time.Duration) (time.Duration, bool) {
+	if retry < 1 || (b.MaxRetries > 0 && retry > b.MaxRetries) {
+		return 0, false
+	}
+	multiplier := b.Multiplier
+	if multiplier <= 0 {
+		multiplier = 2
+	}
+	delay := float64(b.Initial) * math.Pow(multiplier, float64(retry-1))
+	if b.Max > 0 && delay > float64(b.Max) {
+		delay = float64(b.Max)
+	}
+	if b.Jitter > 0 {
+		delay -= delay * math.Min(b.Jitter, 1) * rand.Float64()
+	}
+	return time.Duration(delay), true
+}
+
+// DecorrelatedJitterBackoff picks each delay at random between Base and three times the previous delay, capped at Max.
+// Like ExponentialBackoff it retries indefinitely if MaxRetries is zero.
+type DecorrelatedJitterBackoff struct {
+	Base       time.Duration
+	Max        time.Duration
+	MaxRetries int
+}
+
+func (b DecorrelatedJitterBackoff) NextDelay(retry int, previous time.Duration) (time.Duration, bool) {
+	if retry < 1 || (b.MaxRetries > 0 && retry > b.MaxRetries) {
+		return 0, false
+	}
+	if previous < b.Base {
+		previous = b.Base
+	}
+	delay := b.Base
+	if upper := previous * 3; upper > b.Base {
+		delay += time.Duration(rand.Int63n(int64(upper - b.Base)))
+	}
+	if b.Max > 0 && delay > b.Max {
+		delay = b.Max
+	}
+	return delay, true
+}
+
+// getRetryAfter returns the delay requested by the Retry-After header of a 429 or 503 response, which can either be a
+// number of seconds or an HTTP date
+func getRetryAfter(resp *http.Response) (time.Duration, bool) {
+	if resp == nil || (resp.StatusCode != http.StatusTooManyRequests && resp.StatusCode != http.StatusServiceUnavailable) {
+		return 0, false
+	}
+	retryAfter := strings.TrimSpace(resp.Header.Get("Retry-After"))
+	if retryAfter == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(retryAfter); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+	if date, err := http.ParseTime(retryAfter); err == nil {
+		delay := time.Until(date)
+		if delay < 0 {
+			delay = 0
+		}
+		return delay, true
+	}
+	return 0, false
+}
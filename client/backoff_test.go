@@ -0,0 +1,81 @@
+package mcmaclient
+
+import (
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestFixedBackoff(t *testing.T) {
+	backoff := FixedBackoff{time.Second, 2 * time.Second}
+	for retry, expected := range []time.Duration{time.Second, 2 * time.Second} {
+		if delay, ok := backoff.NextDelay(retry+1, 0); !ok || delay != expected {
+			t.Errorf("retry %d: expected %v, got %v, %v", retry+1, expected, delay, ok)
+		}
+	}
+	if _, ok := backoff.NextDelay(3, 0); ok {
+		t.Error("expected no more retries")
+	}
+}
+
+func TestExponentialBackoff(t *testing.T) {
+	backoff := ExponentialBackoff{Initial: 100 * time.Millisecond, Max: time.Second, MaxRetries: 6}
+	expected := []time.Duration{100, 200, 400, 800, 1000, 1000}
+	for i, e := range expected {
+		if delay, ok := backoff.NextDelay(i+1, 0); !ok || delay != e*time.Millisecond {
+			t.Errorf("retry %d: expected %v, got %v, %v", i+1, e*time.Millisecond, delay, ok)
+		}
+	}
+	if _, ok := backoff.NextDelay(7, 0); ok {
+		t.Error("expected no more retries")
+	}
+
+	backoff.Jitter = 0.5
+	for i := 0; i < 100; i++ {
+		if delay, _ := backoff.NextDelay(3, 0); delay < 200*time.Millisecond || delay > 400*time.Millisecond {
+			t.Fatalf("expected jittered delay between 200ms and 400ms, got %v", delay)
+		}
+	}
+}
+
+func TestDecorrelatedJitterBackoff(t *testing.T) {
+	backoff := DecorrelatedJitterBackoff{Base: 100 * time.Millisecond, Max: time.Second}
+	var delay time.Duration
+	for retry := 1; retry <= 100; retry++ {
+		next, ok := backoff.NextDelay(retry, delay)
+		if !ok {
+			t.Fatal("expected unlimited retries")
+		}
+		upper := 3 * delay
+		if upper < backoff.Base {
+			upper = 3 * backoff.Base
+		}
+		if next < backoff.Base || next > backoff.Max || next > upper {
+			t.Fatalf("retry %d: delay %v out of range after %v", retry, next, delay)
+		}
+		delay = next
+	}
+}
+
+func TestGetRetryAfter(t *testing.T) {
+	tests := []struct {
+		statusCode int
+		retryAfter string
+		expected   time.Duration
+		ok         bool
+	}{
+		{http.StatusTooManyRequests, "3", 3 * time.Second, true},
+		{http.StatusServiceUnavailable, "0", 0, true},
+		{http.StatusServiceUnavailable, time.Now().Add(-time.Minute).UTC().Format(http.TimeFormat), 0, true},
+		{http.StatusInternalServerError, "3", 0, false},
+		{http.StatusTooManyRequests, "soon", 0, false},
+		{http.StatusTooManyRequests, "", 0, false},
+	}
+	for _, test := range tests {
+		resp := &http.Response{StatusCode: test.statusCode, Header: http.Header{}}
+		resp.Header.Set("Retry-After", test.retryAfter)
+		if delay, ok := getRetryAfter(resp); delay != test.expected || ok != test.ok {
+			t.Errorf("%d %q: expected %v, %v, got %v, %v", test.statusCode, test.retryAfter, test.expected, test.ok, delay, ok)
+		}
+	}
+}
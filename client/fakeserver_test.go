@@ -0,0 +1,160 @@
+package mcmaclient
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/ebu/mcma-libraries-go/model"
+)
+
+// fakeMcmaServer is an in-memory service registry and resource store for exercising the client end to end
+type fakeMcmaServer struct {
+	*httptest.Server
+	mutex       sync.Mutex
+	services    []model.Service
+	collections map[string]bool
+	resources   map[string]map[string]interface{}
+	nextId      int
+	requests    []string
+	handlers    map[string]http.HandlerFunc
+}
+
+func newFakeMcmaServer() *fakeMcmaServer {
+	s := &fakeMcmaServer{
+		collections: make(map[string]bool),
+		resources:   make(map[string]map[string]interface{}),
+		handlers:    make(map[string]http.HandlerFunc),
+	}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	return s
+}
+
+func (s *fakeMcmaServer) addService(name string, resourcePaths map[string]string) model.Service {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	var resources []model.ResourceEndpoint
+	for resourceType, path := range resourcePaths {
+		resources = append(resources, model.NewResourceEndpoint(resourceType, s.URL+path))
+		s.collections[path] = true
+	}
+	sort.Slice(resources, func(i, j int) bool { return resources[i].ResourceType < resources[j].ResourceType })
+	service := model.NewServiceNoAuth(name, resources)
+	service.Id = fmt.Sprintf("%s/services/%d", s.URL, len(s.services)+1)
+	s.services = append(s.services, service)
+	return service
+}
+
+func (s *fakeMcmaServer) handle(method, path string, handler http.HandlerFunc) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.handlers[method+" "+path] = handler
+}
+
+func (s *fakeMcmaServer) putResource(id string, resource map[string]interface{}) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	resource["id"] = id
+	s.resources[id] = resource
+}
+
+func (s *fakeMcmaServer) getResource(id string) map[string]interface{} {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.resources[id]
+}
+
+func (s *fakeMcmaServer) getRequests() []string {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return append([]string(nil), s.requests...)
+}
+
+func (s *fakeMcmaServer) countRequests(prefix string) int {
+	count := 0
+	for _, r := range s.getRequests() {
+		if strings.HasPrefix(r, prefix) {
+			count++
+		}
+	}
+	return count
+}
+
+func writeJson(w http.ResponseWriter, statusCode int, body interface{}) {
+	data, _ := json.Marshal(body)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	_, _ = w.Write(data)
+}
+
+func (s *fakeMcmaServer) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mutex.Lock()
+	s.requests = append(s.requests, r.Method+" "+r.URL.RequestURI())
+	handler := s.handlers[r.Method+" "+r.URL.Path]
+	s.mutex.Unlock()
+
+	if handler != nil {
+		handler(w, r)
+		return
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	path := r.URL.Path
+	id := s.URL + path
+	switch {
+	case path == "/services" && r.Method == http.MethodGet:
+		var results []interface{}
+		for _, service := range s.services {
+			results = append(results, service)
+		}
+		writeJson(w, http.StatusOK, model.QueryResults{Results: results})
+	case s.collections[path] && r.Method == http.MethodGet:
+		var ids []string
+		for resourceId := range s.resources {
+			if strings.HasPrefix(resourceId, id+"/") {
+				ids = append(ids, resourceId)
+			}
+		}
+		sort.Strings(ids)
+		var results []interface{}
+		for _, resourceId := range ids {
+			results = append(results, s.resources[resourceId])
+		}
+		writeJson(w, http.StatusOK, model.QueryResults{Results: results})
+	case s.collections[path] && r.Method == http.MethodPost:
+		var resource map[string]interface{}
+		body, _ := io.ReadAll(r.Body)
+		if err := json.Unmarshal(body, &resource); err != nil {
+			writeJson(w, http.StatusBadRequest, model.NewProblemDetail("uri://bad-request", "Bad Request", err.Error()))
+			return
+		}
+		s.nextId++
+		resource["id"] = fmt.Sprintf("%s/%d", id, s.nextId)
+		s.resources[resource["id"].(string)] = resource
+		writeJson(w, http.StatusCreated, resource)
+	case s.resources[id] != nil && r.Method == http.MethodGet:
+		writeJson(w, http.StatusOK, s.resources[id])
+	case s.resources[id] != nil && r.Method == http.MethodPut:
+		var resource map[string]interface{}
+		body, _ := io.ReadAll(r.Body)
+		if err := json.Unmarshal(body, &resource); err != nil {
+			writeJson(w, http.StatusBadRequest, model.NewProblemDetail("uri://bad-request", "Bad Request", err.Error()))
+			return
+		}
+		resource["id"] = id
+		s.resources[id] = resource
+		writeJson(w, http.StatusOK, resource)
+	case s.resources[id] != nil && r.Method == http.MethodDelete:
+		delete(s.resources, id)
+		w.WriteHeader(http.StatusOK)
+	default:
+		writeJson(w, http.StatusNotFound, model.NewProblemDetail("uri://not-found", "Not Found", r.Method+" "+path))
+	}
+}
@@ -0,0 +1,144 @@
+package mcmaclient
+
+import (
+	"context"
+	"reflect"
+)
+
+func typeOf[T any]() reflect.Type {
+	return reflect.TypeOf((*T)(nil)).Elem()
+}
+
+func castResult[T any](r interface{}) T {
+	var result T
+	if r != nil {
+		result = r.(T)
+	}
+	return result
+}
+
+func castResults[T any](rs []interface{}) []T {
+	results := make([]T, 0, len(rs))
+	for _, r := range rs {
+		results = append(results, castResult[T](r))
+	}
+	return results
+}
+
+func GetResourceEndpointClient[T any](serviceClient *ServiceClient) (*ResourceEndpointClient, bool) {
+	return serviceClient.GetResourceEndpointClientByType(typeOf[T]())
+}
+
+// Query returns the resources of type T from every resource endpoint that serves them. If some endpoints fail the
+// results from the others are still returned, together with a *QueryError listing the failures, so check the results
+// before returning on error.
+func Query[T any](resourceManager *ResourceManager, filter *QueryParameters) ([]T, error) {
+	return QueryContext[T](context.Background(), resourceManager, filter)
+}
+
+func QueryContext[T any](ctx context.Context, resourceManager *ResourceManager, filter *QueryParameters) ([]T, error) {
+	// results from the endpoints that succeeded are returned even if others failed
+	results, err := resourceManager.queryByType(ctx, typeOf[T](), filter)
+	if results == nil {
+		return nil, err
+	}
+	return castResults[T](results), err
+}
+
+func Get[T any](resourceManager *ResourceManager, resourceId string) (*T, error) {
+	return GetContext[T](context.Background(), resourceManager, resourceId)
+}
+
+func GetContext[T any](ctx context.Context, resourceManager *ResourceManager, resourceId string) (*T, error) {
+	r, err := resourceManager.GetContext(ctx, typeOf[T](), resourceId)
+	if err != nil || r == nil {
+		return nil, err
+	}
+	result := r.(T)
+	return &result, nil
+}
+
+func Create[T any](resourceManager *ResourceManager, resource T) (T, error) {
+	return CreateContext(context.Background(), resourceManager, resource)
+}
+
+func CreateContext[T any](ctx context.Context, resourceManager *ResourceManager, resource T) (T, error) {
+	r, err := resourceManager.CreateContext(ctx, resource)
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+	return castResult[T](r), nil
+}
+
+func Update[T any](resourceManager *ResourceManager, resource T) (T, error) {
+	return UpdateContext(context.Background(), resourceManager, resource)
+}
+
+func UpdateContext[T any](ctx context.Context, resourceManager *ResourceManager, resource T) (T, error) {
+	r, err := resourceManager.UpdateContext(ctx, resource)
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+	return castResult[T](r), nil
+}
+
+func Delete[T any](resourceManager *ResourceManager, resourceId string) error {
+	return DeleteContext[T](context.Background(), resourceManager, resourceId)
+}
+
+func DeleteContext[T any](ctx context.Context, resourceManager *ResourceManager, resourceId string) error {
+	return resourceManager.DeleteContext(ctx, typeOf[T](), resourceId)
+}
+
+func QueryEndpoint[T any](resourceEndpointClient *ResourceEndpointClient, url string, queryParameters *QueryParameters) ([]T, string, error) {
+	return QueryEndpointContext[T](context.Background(), resourceEndpointClient, url, queryParameters)
+}
+
+func QueryEndpointContext[T any](ctx context.Context, resourceEndpointClient *ResourceEndpointClient, url string, queryParameters *QueryParameters) ([]T, string, error) {
+	queryResults, err := resourceEndpointClient.QueryContext(ctx, typeOf[T](), url, queryParameters)
+	if err != nil {
+		return nil, "", err
+	}
+	return castResults[T](queryResults.Results), queryResults.NextPageStartToken, nil
+}
+
+func GetFromEndpoint[T any](resourceEndpointClient *ResourceEndpointClient, url string) (*T, error) {
+	return GetFromEndpointContext[T](context.Background(), resourceEndpointClient, url)
+}
+
+func GetFromEndpointContext[T any](ctx context.Context, resourceEndpointClient *ResourceEndpointClient, url string) (*T, error) {
+	r, err := resourceEndpointClient.GetContext(ctx, typeOf[T](), url)
+	if err != nil || r == nil {
+		return nil, err
+	}
+	result := r.(T)
+	return &result, nil
+}
+
+func PostToEndpoint[T any](resourceEndpointClient *ResourceEndpointClient, url string, body interface{}) (T, error) {
+	return PostToEndpointContext[T](context.Background(), resourceEndpointClient, url, body)
+}
+
+func PostToEndpointContext[T any](ctx context.Context, resourceEndpointClient *ResourceEndpointClient, url string, body interface{}) (T, error) {
+	r, err := resourceEndpointClient.PostContext(ctx, typeOf[T](), url, body)
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+	return castResult[T](r), nil
+}
+
+func PutToEndpoint[T any](resourceEndpointClient *ResourceEndpointClient, url string, body interface{}) (T, error) {
+	return PutToEndpointContext[T](context.Background(), resourceEndpointClient, url, body)
+}
+
+func PutToEndpointContext[T any](ctx context.Context, resourceEndpointClient *ResourceEndpointClient, url string, body interface{}) (T, error) {
+	r, err := resourceEndpointClient.PutContext(ctx, typeOf[T](), url, body)
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+	return castResult[T](r), nil
+}
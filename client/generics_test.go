@@ -0,0 +1,56 @@
+package mcmaclient
+
+import (
+	"testing"
+
+	"github.com/ebu/mcma-libraries-go/model"
+)
+
+func TestGenericResourceManagerApi(t *testing.T) {
+	server := newFakeMcmaServer()
+	defer server.Close()
+	server.addService("job-processor", map[string]string{"AmeJob": "/ame-jobs"})
+
+	resourceManager := NewResourceManagerWithOptions(server.URL)
+
+	created, err := Create(resourceManager, model.NewAmeJob("profile", map[string]interface{}{"inputFile": "a.mp4"}))
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	if created.Id == "" || created.Type != model.AmeJobType {
+		t.Fatalf("unexpected created job %+v", created)
+	}
+
+	fetched, err := Get[model.AmeJob](resourceManager, created.Id)
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	if fetched == nil || fetched.JobProfileId != "profile" {
+		t.Fatalf("unexpected fetched job %+v", fetched)
+	}
+
+	fetched.Status = model.JobStatusRunning
+	updated, err := Update(resourceManager, *fetched)
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	if updated.Status != model.JobStatusRunning {
+		t.Errorf("unexpected updated job %+v", updated)
+	}
+
+	jobs, err := Query[model.AmeJob](resourceManager, nil)
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	if len(jobs) != 1 || jobs[0].Id != created.Id {
+		t.Errorf("unexpected query results %+v", jobs)
+	}
+
+	if err := Delete[model.AmeJob](resourceManager, created.Id); err != nil {
+		t.Fatalf("%v", err)
+	}
+	missing, err := Get[model.AmeJob](resourceManager, created.Id)
+	if err != nil || missing != nil {
+		t.Errorf("expected deleted job to be missing, got %+v, %v", missing, err)
+	}
+}
@@ -0,0 +1,222 @@
+package mcmaclient
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+const defaultGoogleTokenUri = "https://oauth2.googleapis.com/token"
+
+const defaultGoogleMetadataHost = "metadata.google.internal"
+
+// googleIdTokenLifetime is how long Google issues ID tokens for, used if a token's expiry cannot be read
+const googleIdTokenLifetime = time.Hour
+
+type GoogleIdTokenOptions struct {
+	// Audience returns the audience to get an ID token for. Defaults to the scheme and host of the request url, which
+	// is the service url that Cloud Run checks. Cloud Functions expect the url of the function instead, so set
+	// Audience to return it when calling them.
+	Audience func(req *http.Request) string
+	// MetadataHost overrides the host of the metadata server, which can also be set with GCE_METADATA_HOST
+	MetadataHost string
+	// RefreshBefore is how long before a token expires that a new one is fetched in the background. Defaults to a
+	// minute.
+	RefreshBefore time.Duration
+	HttpClient    *http.Client
+}
+
+// GoogleIdTokenAuthenticator adds a Google-signed ID token for the audience of each request, caching the tokens per
+// audience
+type GoogleIdTokenAuthenticator struct {
+	opts   GoogleIdTokenOptions
+	fetch  func(ctx context.Context, audience string) (string, error)
+	mutex  sync.Mutex
+	caches map[string]*tokenCache
+}
+
+func (googleAuth *GoogleIdTokenAuthenticator) Authenticate(req *http.Request) error {
+	audience := googleAuth.getAudience(req)
+	if audience == "" {
+		return fmt.Errorf("no audience to get a google id token for %s", req.URL.Redacted())
+	}
+	token, err := googleAuth.getCache(audience).get(req.Context())
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+token.value)
+	return nil
+}
+
+func (googleAuth *GoogleIdTokenAuthenticator) getAudience(req *http.Request) string {
+	if googleAuth.opts.Audience != nil {
+		return googleAuth.opts.Audience(req)
+	}
+	if req.URL == nil || req.URL.Host == "" {
+		return ""
+	}
+	return req.URL.Scheme + "://" + req.URL.Host
+}
+
+func (googleAuth *GoogleIdTokenAuthenticator) getCache(audience string) *tokenCache {
+	googleAuth.mutex.Lock()
+	defer googleAuth.mutex.Unlock()
+	cache, found := googleAuth.caches[audience]
+	if !found {
+		cache = newTokenCache(googleAuth.opts.RefreshBefore, func(ctx context.Context) (cachedToken, error) {
+			idToken, err := googleAuth.fetch(ctx, audience)
+			if err != nil {
+				return cachedToken{}, err
+			}
+			expiresAt, ok := getJwtExpiry(idToken)
+			if !ok {
+				expiresAt = time.Now().Add(googleIdTokenLifetime)
+			}
+			return cachedToken{value: idToken, expiresAt: expiresAt}, nil
+		})
+		googleAuth.caches[audience] = cache
+	}
+	return cache
+}
+
+func newGoogleIdTokenAuthenticator(opts GoogleIdTokenOptions, fetch func(ctx context.Context, audience string) (string, error)) *GoogleIdTokenAuthenticator {
+	return &GoogleIdTokenAuthenticator{
+		opts:   opts,
+		fetch:  fetch,
+		caches: make(map[string]*tokenCache),
+	}
+}
+
+type googleServiceAccountKeyJson struct {
+	Type         string `json:"type"`
+	ClientEmail  string `json:"client_email"`
+	PrivateKeyId string `json:"private_key_id"`
+	PrivateKey   string `json:"private_key"`
+	TokenUri     string `json:"token_uri"`
+}
+
+// NewGoogleIdTokenAuthenticatorFromServiceAccountKey gets ID tokens for the service account in a JSON key file
+func NewGoogleIdTokenAuthenticatorFromServiceAccountKey(keyJson []byte, opts GoogleIdTokenOptions) (*GoogleIdTokenAuthenticator, error) {
+	var key googleServiceAccountKeyJson
+	if err := json.Unmarshal(keyJson, &key); err != nil {
+		return nil, fmt.Errorf("failed to parse google service account key: %v", err)
+	}
+	if key.Type != "service_account" {
+		return nil, fmt.Errorf("google credentials must be a service account key, got type '%s'", key.Type)
+	}
+	privateKey, err := parseRsaPrivateKey([]byte(key.PrivateKey))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse private key of google service account %s: %v", key.ClientEmail, err)
+	}
+	tokenUri := key.TokenUri
+	if tokenUri == "" {
+		tokenUri = defaultGoogleTokenUri
+	}
+
+	return newGoogleIdTokenAuthenticator(opts, func(ctx context.Context, audience string) (string, error) {
+		now := time.Now()
+		assertion, err := signJwtRS256(map[string]interface{}{
+			"kid": key.PrivateKeyId,
+		}, map[string]interface{}{
+			"iss":             key.ClientEmail,
+			"aud":             tokenUri,
+			"iat":             now.Unix(),
+			"exp":             now.Add(time.Hour).Unix(),
+			"target_audience": audience,
+		}, privateKey)
+		if err != nil {
+			return "", err
+		}
+
+		form := url.Values{}
+		form.Set("grant_type", "urn:ietf:params:oauth:grant-type:jwt-bearer")
+		form.Set("assertion", assertion)
+		tokenResponse, err := requestToken(ctx, opts.HttpClient, tokenUri, form, nil)
+		if err != nil {
+			return "", err
+		}
+		if tokenResponse.IdToken == "" {
+			return "", fmt.Errorf("token response from %s has no id token", tokenUri)
+		}
+		return tokenResponse.IdToken, nil
+	}), nil
+}
+
+func NewGoogleIdTokenAuthenticatorFromServiceAccountKeyFile(path string, opts GoogleIdTokenOptions) (*GoogleIdTokenAuthenticator, error) {
+	keyJson, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read google service account key from %s: %v", path, err)
+	}
+	return NewGoogleIdTokenAuthenticatorFromServiceAccountKey(keyJson, opts)
+}
+
+// NewGoogleIdTokenAuthenticatorFromMetadataServer gets ID tokens for the service account the code runs as on Compute
+// Engine, Cloud Run, Cloud Functions or GKE with workload identity
+func NewGoogleIdTokenAuthenticatorFromMetadataServer(opts GoogleIdTokenOptions) *GoogleIdTokenAuthenticator {
+	return newGoogleIdTokenAuthenticator(opts, func(ctx context.Context, audience string) (string, error) {
+		metadataHost := opts.MetadataHost
+		if metadataHost == "" {
+			metadataHost = os.Getenv("GCE_METADATA_HOST")
+		}
+		if metadataHost == "" {
+			metadataHost = defaultGoogleMetadataHost
+		}
+		query := url.Values{}
+		query.Set("audience", audience)
+		query.Set("format", "full")
+		metadataUrl := "http://" + metadataHost + "/computeMetadata/v1/instance/service-accounts/default/identity?" + query.Encode()
+
+		req, err := http.NewRequestWithContext(ctx, "GET", metadataUrl, nil)
+		if err != nil {
+			return "", err
+		}
+		req.Header.Set("Metadata-Flavor", "Google")
+
+		httpClient := opts.HttpClient
+		if httpClient == nil {
+			httpClient = http.DefaultClient
+		}
+		resp, err := httpClient.Do(req)
+		if err != nil {
+			return "", fmt.Errorf("failed to get id token from metadata server: %w", err)
+		}
+		defer resp.Body.Close()
+		body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+		if err != nil {
+			return "", fmt.Errorf("failed to read id token from metadata server: %v", err)
+		}
+		if resp.StatusCode >= 400 {
+			return "", fmt.Errorf("failed to get id token from metadata server with status %s: %s", resp.Status, strings.TrimSpace(string(body)))
+		}
+		idToken := strings.TrimSpace(string(body))
+		if idToken == "" {
+			return "", errors.New("metadata server returned an empty id token")
+		}
+		return idToken, nil
+	})
+}
+
+func (resourceManager *ResourceManager) AddGoogleAuthFromServiceAccountKeyFile(path string, opts GoogleIdTokenOptions) error {
+	authenticator, err := NewGoogleIdTokenAuthenticatorFromServiceAccountKeyFile(path, opts)
+	if err != nil {
+		return err
+	}
+	resourceManager.AddGoogleAuth(authenticator)
+	return nil
+}
+
+func (resourceManager *ResourceManager) AddGoogleAuthFromMetadataServer(opts GoogleIdTokenOptions) {
+	resourceManager.AddGoogleAuth(NewGoogleIdTokenAuthenticatorFromMetadataServer(opts))
+}
+
+func (resourceManager *ResourceManager) AddGoogleAuth(authenticator *GoogleIdTokenAuthenticator) {
+	resourceManager.AddAuth("Google", authenticator)
+}
@@ -0,0 +1,200 @@
+package mcmaclient
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ebu/mcma-libraries-go/model"
+)
+
+// newFakeIdToken returns an unsigned JWT for the audience, which is enough for the authenticator to read its expiry
+func newFakeIdToken(audience string, count int) string {
+	header := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"none"}`))
+	claims, _ := json.Marshal(map[string]interface{}{"aud": audience, "n": count, "exp": time.Now().Add(time.Hour).Unix()})
+	return header + "." + base64.RawURLEncoding.EncodeToString(claims) + ".signature"
+}
+
+type fakeGoogleServer struct {
+	*httptest.Server
+	mutex     sync.Mutex
+	audiences []string
+}
+
+func (s *fakeGoogleServer) recordAudience(audience string) int {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.audiences = append(s.audiences, audience)
+	return len(s.audiences)
+}
+
+func (s *fakeGoogleServer) getAudiences() []string {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.audiences
+}
+
+func decodeJwtPart(t *testing.T, part string) map[string]interface{} {
+	data, err := base64.RawURLEncoding.DecodeString(part)
+	if err != nil {
+		t.Errorf("%v", err)
+	}
+	var m map[string]interface{}
+	_ = json.Unmarshal(data, &m)
+	return m
+}
+
+func TestGoogleIdTokenAuthenticatorFromServiceAccountKey(t *testing.T) {
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	der, _ := x509.MarshalPKCS8PrivateKey(privateKey)
+
+	server := &fakeGoogleServer{}
+	server.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = r.ParseForm()
+		parts := strings.Split(r.PostForm.Get("assertion"), ".")
+		if r.PostForm.Get("grant_type") != "urn:ietf:params:oauth:grant-type:jwt-bearer" || len(parts) != 3 {
+			writeJson(w, http.StatusBadRequest, map[string]interface{}{"error": "invalid_grant"})
+			return
+		}
+		signature, _ := base64.RawURLEncoding.DecodeString(parts[2])
+		hash := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+		if err := rsa.VerifyPKCS1v15(&privateKey.PublicKey, crypto.SHA256, hash[:], signature); err != nil {
+			writeJson(w, http.StatusBadRequest, map[string]interface{}{"error": "invalid_grant", "error_description": "bad signature"})
+			return
+		}
+		header, claims := decodeJwtPart(t, parts[0]), decodeJwtPart(t, parts[1])
+		if header["kid"] != "key-1" || claims["iss"] != "mcma@project.iam.gserviceaccount.com" || claims["aud"] != server.URL+"/token" {
+			t.Errorf("unexpected assertion %v %v", header, claims)
+		}
+		audience := claims["target_audience"].(string)
+		count := server.recordAudience(audience)
+		writeJson(w, http.StatusOK, map[string]interface{}{"id_token": newFakeIdToken(audience, count)})
+	}))
+	defer server.Close()
+
+	keyJson, _ := json.Marshal(map[string]interface{}{
+		"type":           "service_account",
+		"client_email":   "mcma@project.iam.gserviceaccount.com",
+		"private_key_id": "key-1",
+		"private_key":    string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})),
+		"token_uri":      server.URL + "/token",
+	})
+	authenticator, err := NewGoogleIdTokenAuthenticatorFromServiceAccountKey(keyJson, GoogleIdTokenOptions{})
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	urls := []string{
+		"https://job-processor-abc.a.run.app/jobs",
+		"https://job-processor-abc.a.run.app/jobs/1",
+		"https://service-registry-abc.a.run.app/services",
+	}
+	for _, u := range urls {
+		req, _ := http.NewRequest("GET", u, nil)
+		if err := authenticator.Authenticate(req); err != nil {
+			t.Fatalf("%v", err)
+		}
+		token := strings.TrimPrefix(req.Header.Get("Authorization"), "Bearer ")
+		expectedAudience := u[:strings.Index(u, ".app")+len(".app")]
+		if claims := decodeJwtPart(t, strings.Split(token, ".")[1]); claims["aud"] != expectedAudience {
+			t.Errorf("%s: expected a token for %s, got %v", u, expectedAudience, claims["aud"])
+		}
+	}
+
+	// tokens are cached per audience
+	if audiences := server.getAudiences(); len(audiences) != 2 {
+		t.Errorf("expected a token request per audience, got %v", audiences)
+	}
+}
+
+func TestGoogleIdTokenAuthenticatorFromMetadataServer(t *testing.T) {
+	server := &fakeGoogleServer{}
+	server.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Metadata-Flavor") != "Google" || r.URL.Path != "/computeMetadata/v1/instance/service-accounts/default/identity" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		audience := r.URL.Query().Get("audience")
+		_, _ = fmt.Fprint(w, newFakeIdToken(audience, server.recordAudience(audience)))
+	}))
+	defer server.Close()
+
+	resourceManager := NewResourceManagerWithOptions(server.URL)
+	resourceManager.AddGoogleAuthFromMetadataServer(GoogleIdTokenOptions{
+		MetadataHost: strings.TrimPrefix(server.URL, "http://"),
+		Audience: func(req *http.Request) string {
+			return "https://europe-west1-project.cloudfunctions.net/job-processor"
+		},
+	})
+	authenticator, err := resourceManager.authProvider.Get("Google")
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	for i := 0; i < 2; i++ {
+		if authorization := authorizationFor(t, authenticator); !strings.HasPrefix(authorization, "Bearer ") {
+			t.Fatalf("expected a bearer token, got %q", authorization)
+		}
+	}
+	if audiences := server.getAudiences(); len(audiences) != 1 || audiences[0] != "https://europe-west1-project.cloudfunctions.net/job-processor" {
+		t.Errorf("expected a single token request for the configured audience, got %v", audiences)
+	}
+}
+
+func TestGoogleIdTokenAuthenticatorUsesHostOfResourceEndpointAsAudience(t *testing.T) {
+	server := newFakeMcmaServer()
+	defer server.Close()
+	server.putResource(server.URL+"/job-processor/jobs/1", map[string]interface{}{"@type": "Job", "status": "Running"})
+	server.handle("GET", "/job-processor/jobs", func(w http.ResponseWriter, r *http.Request) {
+		writeJson(w, http.StatusOK, map[string]interface{}{"results": []interface{}{}})
+	})
+
+	var mutex sync.Mutex
+	var audiences []string
+	authenticator := newGoogleIdTokenAuthenticator(GoogleIdTokenOptions{}, func(ctx context.Context, audience string) (string, error) {
+		mutex.Lock()
+		defer mutex.Unlock()
+		audiences = append(audiences, audience)
+		return newFakeIdToken(audience, len(audiences)), nil
+	})
+	authProvider := &AuthProvider{authenticators: map[string]Authenticator{}}
+	authProvider.Add("Google", authenticator)
+
+	// Cloud Run checks the audience against the service url, so the path of the endpoint is not part of it
+	httpEndpoint := server.URL + "/job-processor/jobs"
+	resourceEndpointClient := &ResourceEndpointClient{
+		authProvider:     authProvider,
+		httpClient:       server.Client(),
+		resourceEndpoint: model.NewResourceEndpoint("Job", httpEndpoint),
+		serviceAuthType:  "Google",
+	}
+	if _, err := resourceEndpointClient.GetContext(context.Background(), reflect.TypeOf(model.Job{}), "1"); err != nil {
+		t.Fatalf("%v", err)
+	}
+	if _, err := resourceEndpointClient.QueryContext(context.Background(), reflect.TypeOf(model.Job{}), "", NewQueryParameters().Where("status", "Running")); err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	mutex.Lock()
+	defer mutex.Unlock()
+	if len(audiences) != 1 || audiences[0] != server.URL {
+		t.Errorf("expected a single token for %s, got %v", server.URL, audiences)
+	}
+}
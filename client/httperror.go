@@ -0,0 +1,55 @@
+package mcmaclient
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/ebu/mcma-libraries-go/model"
+)
+
+type HttpError struct {
+	StatusCode    int
+	Status        string
+	Method        string
+	Url           string
+	Header        http.Header
+	Body          []byte
+	ProblemDetail *model.ProblemDetail
+}
+
+func (httpError *HttpError) Error() string {
+	if httpError.ProblemDetail != nil && (httpError.ProblemDetail.Title != "" || httpError.ProblemDetail.Detail != "") {
+		return fmt.Sprintf("%v %v returned %v: %v: %v", httpError.Method, httpError.Url, httpError.Status, httpError.ProblemDetail.Title, httpError.ProblemDetail.Detail)
+	}
+	return fmt.Sprintf("%v %v returned %v: %v", httpError.Method, httpError.Url, httpError.Status, string(httpError.Body))
+}
+
+func newHttpError(req *http.Request, resp *http.Response) *HttpError {
+	var body bytes.Buffer
+	if resp.Body != nil {
+		_, _ = body.ReadFrom(resp.Body)
+		_ = resp.Body.Close()
+	}
+
+	httpError := &HttpError{
+		StatusCode: resp.StatusCode,
+		Status:     resp.Status,
+		Method:     req.Method,
+		Url:        req.URL.String(),
+		Header:     resp.Header,
+		Body:       body.Bytes(),
+	}
+
+	// MCMA services return RFC 7807 problem details for most errors, but fall back to the raw body if it isn't one
+	trimmedBody := bytes.TrimSpace(httpError.Body)
+	if len(trimmedBody) > 0 && trimmedBody[0] == '{' {
+		var problemDetail model.ProblemDetail
+		if err := json.Unmarshal(trimmedBody, &problemDetail); err == nil {
+			httpError.ProblemDetail = &problemDetail
+		}
+	}
+
+	return httpError
+}
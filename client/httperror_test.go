@@ -0,0 +1,62 @@
+package mcmaclient
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestHttpErrorWithProblemDetail(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/problem+json")
+		w.WriteHeader(http.StatusConflict)
+		_, _ = w.Write([]byte(`{ "type": "uri://mcma.ebu.ch/rfc7807/conflict", "title": "Conflict", "detail": "job already exists", "instance": "/jobs/1", "jobId": "1" }`))
+	}))
+	defer server.Close()
+
+	client := &McmaHttpClient{httpClient: server.Client()}
+	_, err := client.Get(server.URL+"/jobs/1", true)
+	if err == nil {
+		t.Fatalf("expected error for 409 response")
+	}
+
+	var httpError *HttpError
+	if !errors.As(err, &httpError) {
+		t.Fatalf("expected *HttpError, got %T: %v", err, err)
+	}
+	if httpError.StatusCode != http.StatusConflict || httpError.Method != "GET" {
+		t.Errorf("unexpected status code or method: %d %s", httpError.StatusCode, httpError.Method)
+	}
+	if httpError.Header.Get("Content-Type") != "application/problem+json" {
+		t.Errorf("expected response headers to be exposed")
+	}
+	if httpError.ProblemDetail == nil {
+		t.Fatalf("expected problem detail to be parsed from %s", string(httpError.Body))
+	}
+	if httpError.ProblemDetail.ProblemType != "uri://mcma.ebu.ch/rfc7807/conflict" || httpError.ProblemDetail.Detail != "job already exists" {
+		t.Errorf("unexpected problem detail %+v", httpError.ProblemDetail)
+	}
+	if httpError.ProblemDetail.Custom["jobId"] != "1" {
+		t.Errorf("expected extension member jobId to be kept, got %v", httpError.ProblemDetail.Custom)
+	}
+}
+
+func TestHttpErrorWithPlainBody(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte("bad request"))
+	}))
+	defer server.Close()
+
+	client := &McmaHttpClient{httpClient: server.Client()}
+	_, err := client.Delete(server.URL + "/jobs/1")
+
+	var httpError *HttpError
+	if !errors.As(err, &httpError) {
+		t.Fatalf("expected *HttpError, got %T: %v", err, err)
+	}
+	if httpError.ProblemDetail != nil || string(httpError.Body) != "bad request" {
+		t.Errorf("unexpected error body %q / problem detail %+v", string(httpError.Body), httpError.ProblemDetail)
+	}
+}
@@ -0,0 +1,42 @@
+package mcmaclient
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+)
+
+const IdempotencyKeyHeader = "Idempotency-Key"
+
+type idempotencyKeyKey struct{}
+
+// WithIdempotencyKey returns a context that makes requests sent with it carry the given idempotency key. Requests
+// with an idempotency key are retried even if their method is not idempotent, such as POST, so the key should only be
+// used with services that deduplicate requests by it.
+func WithIdempotencyKey(ctx context.Context, key string) context.Context {
+	return context.WithValue(ctx, idempotencyKeyKey{}, key)
+}
+
+func getIdempotencyKey(ctx context.Context) string {
+	key, _ := ctx.Value(idempotencyKeyKey{}).(string)
+	return key
+}
+
+// NewIdempotencyKey generates a random key to use with WithIdempotencyKey
+func NewIdempotencyKey() (string, error) {
+	key, err := newUuid()
+	if err != nil {
+		return "", fmt.Errorf("failed to generate idempotency key: %v", err)
+	}
+	return key, nil
+}
+
+// isRetryableRequest returns whether a request can safely be sent more than once, which is the case for idempotent
+// methods and for requests with an idempotency key
+func isRetryableRequest(req *http.Request) bool {
+	switch req.Method {
+	case "", http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return req.Header.Get(IdempotencyKeyHeader) != ""
+}
@@ -0,0 +1,78 @@
+package mcmaclient
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestRetriesAreMethodAware(t *testing.T) {
+	var mutex sync.Mutex
+	var keys []string
+	getKeys := func() []string {
+		mutex.Lock()
+		defer mutex.Unlock()
+		return keys
+	}
+	resetKeys := func() {
+		mutex.Lock()
+		defer mutex.Unlock()
+		keys = nil
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mutex.Lock()
+		defer mutex.Unlock()
+		keys = append(keys, r.Header.Get(IdempotencyKeyHeader))
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	client := &McmaHttpClient{httpClient: server.Client()}
+	retryOpts := RetryOptions{Intervals: []time.Duration{time.Millisecond, time.Millisecond}}
+
+	if _, err := client.PostWithRetries(server.URL, nil, retryOpts); err == nil {
+		t.Fatal("expected an error")
+	}
+	if len(getKeys()) != 1 {
+		t.Errorf("expected a POST without an idempotency key to be sent once, got %d attempts", len(getKeys()))
+	}
+
+	resetKeys()
+	if _, err := client.PutWithRetries(server.URL, nil, retryOpts); err == nil {
+		t.Fatal("expected an error")
+	}
+	if len(getKeys()) != 3 {
+		t.Errorf("expected a PUT to be retried, got %d attempts", len(getKeys()))
+	}
+
+	resetKeys()
+	key, err := NewIdempotencyKey()
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	ctx := WithIdempotencyKey(context.Background(), key)
+	if _, err := client.PostWithRetriesContext(ctx, server.URL, nil, retryOpts); err == nil {
+		t.Fatal("expected an error")
+	}
+	if len(getKeys()) != 3 {
+		t.Fatalf("expected a POST with an idempotency key to be retried, got %d attempts", len(getKeys()))
+	}
+	for _, k := range getKeys() {
+		if k != key {
+			t.Errorf("expected every attempt to carry key %s, got %s", key, k)
+		}
+	}
+}
+
+func TestNewIdempotencyKey(t *testing.T) {
+	uuid := regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)
+	key1, _ := NewIdempotencyKey()
+	key2, _ := NewIdempotencyKey()
+	if !uuid.MatchString(key1) || key1 == key2 {
+		t.Errorf("expected distinct uuids, got %s and %s", key1, key2)
+	}
+}
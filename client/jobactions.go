@@ -0,0 +1,86 @@
+package mcmaclient
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/ebu/mcma-libraries-go/model"
+)
+
+func (resourceManager *ResourceManager) getJobResourceEndpointForId(ctx context.Context, jobId string) (*ResourceEndpointClient, error) {
+	if jobId == "" {
+		return nil, fmt.Errorf("job id must be provided")
+	}
+	if err := resourceManager.EnsureInitContext(ctx); err != nil {
+		return nil, err
+	}
+	for _, s := range resourceManager.getServices() {
+		if resourceEndpointClient, matched := s.GetResourceEndpointClientByTypeNameAndUrl(model.JobType, jobId); matched {
+			return resourceEndpointClient, nil
+		}
+	}
+	return nil, nil
+}
+
+func (resourceManager *ResourceManager) postJobAction(ctx context.Context, jobId string, action string) error {
+	resourceEndpointClient, err := resourceManager.getJobResourceEndpointForId(ctx, jobId)
+	if err != nil {
+		return err
+	}
+	actionUrl := strings.TrimSuffix(jobId, "/") + "/" + action
+	if resourceEndpointClient != nil {
+		_, err = resourceEndpointClient.PostContext(ctx, nil, actionUrl, nil)
+	} else {
+		_, err = resourceManager.getMcmaHttpClient().PostContext(ctx, actionUrl, nil)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to %s job %s: %w", action, jobId, err)
+	}
+	return nil
+}
+
+func (resourceManager *ResourceManager) CancelJob(jobId string) error {
+	return resourceManager.CancelJobContext(context.Background(), jobId)
+}
+
+func (resourceManager *ResourceManager) CancelJobContext(ctx context.Context, jobId string) error {
+	return resourceManager.postJobAction(ctx, jobId, "cancel")
+}
+
+func (resourceManager *ResourceManager) RestartJob(jobId string) error {
+	return resourceManager.RestartJobContext(context.Background(), jobId)
+}
+
+func (resourceManager *ResourceManager) RestartJobContext(ctx context.Context, jobId string) error {
+	return resourceManager.postJobAction(ctx, jobId, "restart")
+}
+
+func (resourceManager *ResourceManager) GetJobExecutions(jobId string) ([]model.JobExecution, error) {
+	return resourceManager.GetJobExecutionsContext(context.Background(), jobId)
+}
+
+func (resourceManager *ResourceManager) GetJobExecutionsContext(ctx context.Context, jobId string) ([]model.JobExecution, error) {
+	resourceEndpointClient, err := resourceManager.getJobResourceEndpointForId(ctx, jobId)
+	if err != nil {
+		return nil, err
+	}
+
+	executionsUrl := strings.TrimSuffix(jobId, "/") + "/executions"
+	if resourceEndpointClient != nil {
+		return QueryEndpointAll[model.JobExecution](ctx, resourceEndpointClient, executionsUrl, nil, 0)
+	}
+
+	mcmaHttpClient := resourceManager.getMcmaHttpClient()
+	iterator := mcmaHttpClient.newQueryPageIterator(reflect.TypeOf(model.JobExecution{}), executionsUrl, nil, 0, mcmaHttpClient.getRetryOptions())
+	var jobExecutions []model.JobExecution
+	for iterator.HasNext() {
+		page, err := iterator.Next(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get executions for job %s: %w", jobId, err)
+		}
+		jobExecutions = append(jobExecutions, castResults[model.JobExecution](page.Results)...)
+	}
+	return jobExecutions, nil
+}
@@ -0,0 +1,81 @@
+package mcmaclient
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/ebu/mcma-libraries-go/model"
+)
+
+func TestJobActions(t *testing.T) {
+	server, resourceManager := newJobProcessorServer()
+	defer server.Close()
+
+	jobId := server.URL + "/jobs/1"
+	server.putResource(jobId, map[string]interface{}{"@type": "Job", "status": "Running"})
+	server.handle("POST", "/jobs/1/cancel", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	server.handle("POST", "/jobs/1/restart", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	server.handle("GET", "/jobs/1/executions", func(w http.ResponseWriter, r *http.Request) {
+		execution := model.NewJobExecution("https://service/api/job-assignments/1")
+		execution.Id = jobId + "/executions/1"
+		execution.Status = model.JobStatusCanceled
+		writeJson(w, http.StatusOK, model.QueryResults{Results: []interface{}{execution}})
+	})
+
+	if err := resourceManager.CancelJob(jobId); err != nil {
+		t.Fatalf("%v", err)
+	}
+	if err := resourceManager.RestartJob(jobId); err != nil {
+		t.Fatalf("%v", err)
+	}
+	executions, err := resourceManager.GetJobExecutions(jobId)
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	if len(executions) != 1 || executions[0].Status != model.JobStatusCanceled {
+		t.Errorf("unexpected executions %+v", executions)
+	}
+	if server.countRequests("POST /jobs/1/cancel") != 1 || server.countRequests("POST /jobs/1/restart") != 1 {
+		t.Errorf("unexpected requests %v", server.getRequests())
+	}
+
+	if err := resourceManager.CancelJob(server.URL + "/jobs/2"); err == nil {
+		t.Errorf("expected error cancelling unknown job")
+	}
+}
+
+func TestGetJobExecutionsFollowsPages(t *testing.T) {
+	server, resourceManager := newJobProcessorServer()
+	defer server.Close()
+
+	handleExecutionPages := func(path string) {
+		server.handle("GET", path, func(w http.ResponseWriter, r *http.Request) {
+			execution := model.NewJobExecution("https://service/api/job-assignments/1")
+			var nextPageStartToken string
+			if r.URL.Query().Get("pageStartToken") == "" {
+				execution.Id = "1"
+				nextPageStartToken = "next"
+			} else {
+				execution.Id = "2"
+			}
+			writeJson(w, http.StatusOK, model.QueryResults{Results: []interface{}{execution}, NextPageStartToken: nextPageStartToken})
+		})
+	}
+	handleExecutionPages("/jobs/1/executions")
+	// not under the job processor's Job endpoint, so read without a resource endpoint client
+	handleExecutionPages("/other/jobs/1/executions")
+
+	for _, jobId := range []string{server.URL + "/jobs/1", server.URL + "/other/jobs/1"} {
+		executions, err := resourceManager.GetJobExecutions(jobId)
+		if err != nil {
+			t.Fatalf("%s: %v", jobId, err)
+		}
+		if len(executions) != 2 || executions[0].Id != "1" || executions[1].Id != "2" {
+			t.Errorf("%s: expected the executions from both pages, got %+v", jobId, executions)
+		}
+	}
+}
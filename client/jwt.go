@@ -0,0 +1,88 @@
+package mcmaclient
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// signJwtRS256 creates a JWT signed with RS256, as used for client assertions to token endpoints
+func signJwtRS256(header map[string]interface{}, claims map[string]interface{}, privateKey *rsa.PrivateKey) (string, error) {
+	jwtHeader := map[string]interface{}{
+		"alg": "RS256",
+		"typ": "JWT",
+	}
+	for key, value := range header {
+		jwtHeader[key] = value
+	}
+	headerJson, err := json.Marshal(jwtHeader)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal jwt header: %v", err)
+	}
+	claimsJson, err := json.Marshal(claims)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal jwt claims: %v", err)
+	}
+
+	signingInput := base64.RawURLEncoding.EncodeToString(headerJson) + "." + base64.RawURLEncoding.EncodeToString(claimsJson)
+	hash := sha256.Sum256([]byte(signingInput))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, privateKey, crypto.SHA256, hash[:])
+	if err != nil {
+		return "", fmt.Errorf("failed to sign jwt: %v", err)
+	}
+	return signingInput + "." + base64.RawURLEncoding.EncodeToString(signature), nil
+}
+
+// parseRsaPrivateKey parses a PEM encoded RSA private key in either PKCS #1 or PKCS #8 form
+func parseRsaPrivateKey(pemData []byte) (*rsa.PrivateKey, error) {
+	for {
+		var block *pem.Block
+		block, pemData = pem.Decode(pemData)
+		if block == nil {
+			return nil, errors.New("no private key found in pem data")
+		}
+		switch block.Type {
+		case "RSA PRIVATE KEY":
+			return x509.ParsePKCS1PrivateKey(block.Bytes)
+		case "PRIVATE KEY":
+			key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+			if err != nil {
+				return nil, err
+			}
+			rsaKey, ok := key.(*rsa.PrivateKey)
+			if !ok {
+				return nil, fmt.Errorf("private key must be an RSA key, got %T", key)
+			}
+			return rsaKey, nil
+		}
+	}
+}
+
+// getJwtExpiry reads the exp claim of a JWT without verifying it, which is only safe for tokens received directly
+// from the issuer
+func getJwtExpiry(token string) (time.Time, bool) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return time.Time{}, false
+	}
+	claimsJson, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(parts[1], "="))
+	if err != nil {
+		return time.Time{}, false
+	}
+	var claims struct {
+		Exp int64 `json:"exp"`
+	}
+	if err := json.Unmarshal(claimsJson, &claims); err != nil || claims.Exp == 0 {
+		return time.Time{}, false
+	}
+	return time.Unix(claims.Exp, 0), true
+}
@@ -0,0 +1,13 @@
+package mcmaclient
+
+// Logger receives diagnostics for failures that the resource manager recovers from on its own, such as a failed
+// background refresh of the service registry. The standard library's *log.Logger satisfies it.
+type Logger interface {
+	Printf(format string, v ...interface{})
+}
+
+func (resourceManager *ResourceManager) logf(format string, v ...interface{}) {
+	if resourceManager.logger != nil {
+		resourceManager.logger.Printf(format, v...)
+	}
+}
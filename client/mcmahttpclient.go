@@ -2,8 +2,7 @@ package mcmaclient
 
 import (
 	"bytes"
-	"encoding/base64"
-	"encoding/json"
+	"context"
 	"fmt"
 	"io"
 	"net/http"
@@ -16,6 +15,16 @@ type McmaHttpClient struct {
 	httpClient    *http.Client
 	authenticator *Authenticator
 	tracker       *model.McmaTracker
+	retryOptions  *RetryOptions
+	userAgent     string
+	middlewares   []Middleware
+}
+
+func (client *McmaHttpClient) getRetryOptions() RetryOptions {
+	if client.retryOptions != nil {
+		return *client.retryOptions
+	}
+	return DefaultRetryOptions
 }
 
 type nopCloser struct {
@@ -28,20 +37,20 @@ func (nopCloser) Close() error {
 
 func getHttpErrorResponse(req *http.Request, resp *http.Response) error {
 	// return an error with details from the body if possible
-	var errorBody bytes.Buffer
-	if resp.Body != nil {
-		_, _ = errorBody.ReadFrom(resp.Body)
-	}
-	return fmt.Errorf("%v %v returned %v: %v", req.Method, req.URL, resp.Status, errorBody.String())
+	return newHttpError(req, resp)
 }
 
 func newHttpRequest(method string, url string, body *bytes.Reader) (*http.Request, error) {
+	return newHttpRequestWithContext(context.Background(), method, url, body)
+}
+
+func newHttpRequestWithContext(ctx context.Context, method string, url string, body *bytes.Reader) (*http.Request, error) {
 	var req *http.Request
 	var err error
 	if body != nil {
-		req, err = http.NewRequest(method, url, nopCloser{body})
+		req, err = http.NewRequestWithContext(ctx, method, url, nopCloser{body})
 	} else {
-		req, err = http.NewRequest(method, url, nil)
+		req, err = http.NewRequestWithContext(ctx, method, url, nil)
 	}
 	if err != nil {
 		return nil, err
@@ -58,82 +67,111 @@ func newHttpRequest(method string, url string, body *bytes.Reader) (*http.Reques
 }
 
 func (client *McmaHttpClient) Get(url string, throwOn404 bool) (*http.Response, error) {
-	return client.GetWithRetries(url, throwOn404, DefaultRetryOptions)
+	return client.GetWithRetriesContext(context.Background(), url, throwOn404, client.getRetryOptions())
 }
 func (client *McmaHttpClient) GetWithRetries(url string, throwOn404 bool, retryOpts RetryOptions) (*http.Response, error) {
-	req, err := newHttpRequest("GET", url, nil)
+	return client.GetWithRetriesContext(context.Background(), url, throwOn404, retryOpts)
+}
+func (client *McmaHttpClient) GetContext(ctx context.Context, url string, throwOn404 bool) (*http.Response, error) {
+	return client.GetWithRetriesContext(ctx, url, throwOn404, client.getRetryOptions())
+}
+func (client *McmaHttpClient) GetWithRetriesContext(ctx context.Context, url string, throwOn404 bool, retryOpts RetryOptions) (*http.Response, error) {
+	req, err := newHttpRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
 		return nil, err
 	}
-	return client.SendWithRetries(req, throwOn404, retryOpts)
+	return client.SendWithRetriesContext(ctx, req, throwOn404, retryOpts)
 }
 
 func (client *McmaHttpClient) Post(url string, body *bytes.Reader) (*http.Response, error) {
-	return client.PostWithRetries(url, body, DefaultRetryOptions)
+	return client.PostWithRetriesContext(context.Background(), url, body, client.getRetryOptions())
 }
 func (client *McmaHttpClient) PostWithRetries(url string, body *bytes.Reader, retryOpts RetryOptions) (*http.Response, error) {
-	req, err := newHttpRequest("POST", url, body)
+	return client.PostWithRetriesContext(context.Background(), url, body, retryOpts)
+}
+func (client *McmaHttpClient) PostContext(ctx context.Context, url string, body *bytes.Reader) (*http.Response, error) {
+	return client.PostWithRetriesContext(ctx, url, body, client.getRetryOptions())
+}
+func (client *McmaHttpClient) PostWithRetriesContext(ctx context.Context, url string, body *bytes.Reader, retryOpts RetryOptions) (*http.Response, error) {
+	req, err := newHttpRequestWithContext(ctx, "POST", url, body)
 	if err != nil {
 		return nil, err
 	}
 	req.Header.Set("Content-Type", "application/json")
-	return client.SendWithRetries(req, true, retryOpts)
+	return client.SendWithRetriesContext(ctx, req, true, retryOpts)
 }
 
 func (client *McmaHttpClient) Put(url string, body *bytes.Reader) (*http.Response, error) {
-	return client.PutWithRetries(url, body, DefaultRetryOptions)
+	return client.PutWithRetriesContext(context.Background(), url, body, client.getRetryOptions())
 }
 func (client *McmaHttpClient) PutWithRetries(url string, body *bytes.Reader, retryOpts RetryOptions) (*http.Response, error) {
-	req, err := newHttpRequest("PUT", url, body)
+	return client.PutWithRetriesContext(context.Background(), url, body, retryOpts)
+}
+func (client *McmaHttpClient) PutContext(ctx context.Context, url string, body *bytes.Reader) (*http.Response, error) {
+	return client.PutWithRetriesContext(ctx, url, body, client.getRetryOptions())
+}
+func (client *McmaHttpClient) PutWithRetriesContext(ctx context.Context, url string, body *bytes.Reader, retryOpts RetryOptions) (*http.Response, error) {
+	req, err := newHttpRequestWithContext(ctx, "PUT", url, body)
 	if err != nil {
 		return nil, err
 	}
 	req.Header.Set("Content-Type", "application/json")
-	return client.SendWithRetries(req, true, retryOpts)
+	return client.SendWithRetriesContext(ctx, req, true, retryOpts)
 }
 
 func (client *McmaHttpClient) Delete(url string) (*http.Response, error) {
-	return client.DeleteWithRetries(url, DefaultRetryOptions)
+	return client.DeleteWithRetriesContext(context.Background(), url, client.getRetryOptions())
 }
 func (client *McmaHttpClient) DeleteWithRetries(url string, retryOpts RetryOptions) (*http.Response, error) {
-	req, err := newHttpRequest("DELETE", url, nil)
+	return client.DeleteWithRetriesContext(context.Background(), url, retryOpts)
+}
+func (client *McmaHttpClient) DeleteContext(ctx context.Context, url string) (*http.Response, error) {
+	return client.DeleteWithRetriesContext(ctx, url, client.getRetryOptions())
+}
+func (client *McmaHttpClient) DeleteWithRetriesContext(ctx context.Context, url string, retryOpts RetryOptions) (*http.Response, error) {
+	req, err := newHttpRequestWithContext(ctx, "DELETE", url, nil)
 	if err != nil {
 		return nil, err
 	}
-	return client.SendWithRetries(req, true, retryOpts)
+	return client.SendWithRetriesContext(ctx, req, true, retryOpts)
 }
 
 func (client *McmaHttpClient) Send(req *http.Request, throwOn404 bool) (*http.Response, error) {
-	return client.SendWithRetries(req, throwOn404, DefaultRetryOptions)
+	return client.SendWithRetriesContext(req.Context(), req, throwOn404, client.getRetryOptions())
+}
+func (client *McmaHttpClient) SendContext(ctx context.Context, req *http.Request, throwOn404 bool) (*http.Response, error) {
+	return client.SendWithRetriesContext(ctx, req, throwOn404, client.getRetryOptions())
 }
 
 func (client *McmaHttpClient) SendWithRetries(req *http.Request, throwOn404 bool, retryOpts RetryOptions) (*http.Response, error) {
+	return client.SendWithRetriesContext(req.Context(), req, throwOn404, retryOpts)
+}
+
+func (client *McmaHttpClient) SendWithRetriesContext(ctx context.Context, req *http.Request, throwOn404 bool, retryOpts RetryOptions) (*http.Response, error) {
 	start := time.Now()
+	ctx, state := withRetryState(ctx)
+	req = req.Clone(ctx)
 
-	if client.tracker != nil {
-		tracker := req.Header.Get("mcma-tracker")
-		if tracker != "" {
-			req.Header.Del("mcma-tracker")
-		}
-		trackerJson, err := json.Marshal(&client.tracker)
-		if err != nil {
-			return nil, fmt.Errorf("failed to marshal MCMA tracker to json: %v", err)
-		}
-		trackerBase64 := base64.StdEncoding.EncodeToString(trackerJson)
-		req.Header.Set("mcma-tracker", trackerBase64)
+	// the idempotency key is set once so that every attempt carries the same key
+	if key := getIdempotencyKey(ctx); key != "" && req.Header.Get(IdempotencyKeyHeader) == "" {
+		req.Header.Set(IdempotencyKeyHeader, key)
 	}
 
+	var authenticator Authenticator
 	if client.authenticator != nil {
-		authenticator := *client.authenticator
-		if authenticator != nil {
-			err := authenticator.Authenticate(req)
-			if err != nil {
-				return nil, err
-			}
-		}
+		authenticator = *client.authenticator
 	}
 
-	done, resp, err := ExecuteWithRetries(client.httpClient, req, retryOpts)
+	// user middlewares see the request first, followed by the built-in retry, tracker and auth middlewares. Retry wraps
+	// the others so that every attempt is tracked and authenticated again, as signatures can expire between attempts.
+	middlewares := append([]Middleware{}, client.middlewares...)
+	middlewares = append(middlewares,
+		RetryMiddleware(retryOpts),
+		TrackerMiddleware(client.tracker),
+		UserAgentMiddleware(client.userAgent),
+		AuthMiddleware(authenticator),
+	)
+	resp, err := chainMiddleware(RoundTripperFunc(client.httpClient.Do), middlewares...).RoundTrip(req)
 
 	// connectivity/network or code error
 	if err != nil {
@@ -141,9 +179,9 @@ func (client *McmaHttpClient) SendWithRetries(req *http.Request, throwOn404 bool
 	}
 
 	// we retried until we hit the limit
-	if !done {
+	if state.exhausted {
 		lastRespErr := getHttpErrorResponse(req, resp)
-		return resp, fmt.Errorf("failed to do %v to %v after %v ms - last err: %v", req.Method, req.URL, start.UnixMilli()-time.Now().UnixMilli(), lastRespErr)
+		return resp, fmt.Errorf("failed to do %v to %v after %v ms - last err: %w", req.Method, req.URL, time.Since(start).Milliseconds(), lastRespErr)
 	}
 
 	// non-error response (or possible explicit exception for 404)
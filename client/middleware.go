@@ -0,0 +1,109 @@
+package mcmaclient
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/ebu/mcma-libraries-go/model"
+)
+
+// Middleware wraps the execution of a request. Middlewares can inspect or modify the request before passing it on to
+// next, and inspect or replace the response on the way back.
+type Middleware func(next http.RoundTripper) http.RoundTripper
+
+// RoundTripperFunc adapts a function to an http.RoundTripper
+type RoundTripperFunc func(req *http.Request) (*http.Response, error)
+
+func (f RoundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// chainMiddleware wraps transport in the middlewares so that the first middleware is the first to see the request
+func chainMiddleware(transport http.RoundTripper, middlewares ...Middleware) http.RoundTripper {
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		transport = middlewares[i](transport)
+	}
+	return transport
+}
+
+// TrackerMiddleware sets the mcma-tracker header from the given tracker, replacing any tracker already on the request
+func TrackerMiddleware(tracker *model.McmaTracker) Middleware {
+	return func(next http.RoundTripper) http.RoundTripper {
+		if tracker == nil {
+			return next
+		}
+		return RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			trackerJson, err := json.Marshal(tracker)
+			if err != nil {
+				return nil, fmt.Errorf("failed to marshal MCMA tracker to json: %v", err)
+			}
+			req = req.Clone(req.Context())
+			req.Header.Set("mcma-tracker", base64.StdEncoding.EncodeToString(trackerJson))
+			return next.RoundTrip(req)
+		})
+	}
+}
+
+// UserAgentMiddleware sets the User-Agent header on requests that do not already have one
+func UserAgentMiddleware(userAgent string) Middleware {
+	return func(next http.RoundTripper) http.RoundTripper {
+		if userAgent == "" {
+			return next
+		}
+		return RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			if req.Header.Get("User-Agent") != "" {
+				return next.RoundTrip(req)
+			}
+			req = req.Clone(req.Context())
+			req.Header.Set("User-Agent", userAgent)
+			return next.RoundTrip(req)
+		})
+	}
+}
+
+func AuthMiddleware(authenticator Authenticator) Middleware {
+	return func(next http.RoundTripper) http.RoundTripper {
+		if authenticator == nil {
+			return next
+		}
+		return RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			req = req.Clone(req.Context())
+			if err := authenticator.Authenticate(req); err != nil {
+				return nil, err
+			}
+			return next.RoundTrip(req)
+		})
+	}
+}
+
+type retryStateKey struct{}
+
+// retryState lets the retry middleware tell McmaHttpClient that it gave up on a request, as opposed to the request
+// succeeding or failing with a status that is not retried
+type retryState struct {
+	exhausted bool
+}
+
+// RetryMiddleware retries the rest of the chain according to opts. When the retries are exhausted the last response
+// is returned.
+func RetryMiddleware(opts RetryOptions) Middleware {
+	return func(next http.RoundTripper) http.RoundTripper {
+		return RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			done, resp, err := executeWithRetries(req.Context(), next.RoundTrip, req, opts)
+			if !done && err == nil {
+				if state, ok := req.Context().Value(retryStateKey{}).(*retryState); ok {
+					state.exhausted = true
+				}
+			}
+			return resp, err
+		})
+	}
+}
+
+func withRetryState(ctx context.Context) (context.Context, *retryState) {
+	state := &retryState{}
+	return context.WithValue(ctx, retryStateKey{}, state), state
+}
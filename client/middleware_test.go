@@ -0,0 +1,95 @@
+package mcmaclient
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ebu/mcma-libraries-go/model"
+)
+
+func TestMiddlewareChainOrder(t *testing.T) {
+	server := newFakeMcmaServer()
+	defer server.Close()
+	server.addService("job-processor", map[string]string{"Job": "/jobs"})
+
+	var mutex sync.Mutex
+	var serverRequestIds []string
+	server.handle("GET", "/jobs/1", func(w http.ResponseWriter, r *http.Request) {
+		mutex.Lock()
+		defer mutex.Unlock()
+		serverRequestIds = append(serverRequestIds, r.Header.Get("X-Request-Id"))
+		if len(serverRequestIds) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		writeJson(w, http.StatusOK, map[string]interface{}{"@type": "Job", "id": server.URL + "/jobs/1"})
+	})
+
+	var calls []string
+	requestId := func(next http.RoundTripper) http.RoundTripper {
+		return RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			if req.Header.Get("mcma-tracker") != "" {
+				t.Error("expected user middlewares to run before the tracker middleware")
+			}
+			calls = append(calls, req.Method+" "+req.URL.Path)
+			req = req.Clone(req.Context())
+			req.Header.Set("X-Request-Id", "req-1")
+			return next.RoundTrip(req)
+		})
+	}
+
+	resourceManager := NewResourceManagerWithOptions(server.URL,
+		WithTracker(&model.McmaTracker{Id: "tracker-1"}),
+		WithRetryOptions(RetryOptions{ShouldRetry: DefaultShouldRetry, Intervals: []time.Duration{time.Millisecond}}),
+		WithMiddleware(requestId),
+	)
+	job, err := Get[model.Job](resourceManager, server.URL+"/jobs/1")
+	if err != nil || job == nil {
+		t.Fatalf("expected job, got %v, %v", job, err)
+	}
+
+	// the retry middleware sits inside the user middleware, so the user middleware sees the request once
+	if len(calls) != 2 || calls[0] != "GET /services" || calls[1] != "GET /jobs/1" {
+		t.Errorf("unexpected calls through middleware %v", calls)
+	}
+	mutex.Lock()
+	defer mutex.Unlock()
+	if len(serverRequestIds) != 2 || serverRequestIds[0] != "req-1" || serverRequestIds[1] != "req-1" {
+		t.Errorf("expected the request id on every attempt, got %v", serverRequestIds)
+	}
+}
+
+func TestMiddlewareCanShortCircuit(t *testing.T) {
+	server := newFakeMcmaServer()
+	defer server.Close()
+	service := server.addService("job-processor", map[string]string{"Job": "/jobs"})
+
+	cached := func(next http.RoundTripper) http.RoundTripper {
+		return RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			body := `{"@type": "Job", "id": "` + req.URL.String() + `", "status": "Completed"}`
+			return &http.Response{
+				StatusCode:    http.StatusOK,
+				Header:        http.Header{"Content-Type": []string{"application/json"}},
+				Body:          io.NopCloser(strings.NewReader(body)),
+				ContentLength: int64(len(body)),
+				Request:       req,
+			}, nil
+		})
+	}
+
+	resourceManager := NewResourceManagerFromServices([]model.Service{service}, WithMiddleware(cached))
+	job, err := Get[model.Job](resourceManager, server.URL+"/jobs/1")
+	if err != nil || job == nil {
+		t.Fatalf("expected job, got %v, %v", job, err)
+	}
+	if job.Status != model.JobStatusCompleted {
+		t.Errorf("expected the cached job, got %+v", job)
+	}
+	if count := server.countRequests("GET /jobs"); count != 0 {
+		t.Errorf("expected no requests to reach the server, got %d", count)
+	}
+}
@@ -0,0 +1,95 @@
+package mcmaclient
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+type OAuth2ClientCredentialsConfig struct {
+	TokenUrl     string
+	ClientId     string
+	ClientSecret string
+	Scopes       []string
+	Audience     string
+	// EndpointParams are added to the token request as they are
+	EndpointParams url.Values
+	// ClientSecretInBody sends the client id and secret as form parameters instead of with basic auth
+	ClientSecretInBody bool
+	// RefreshBefore is how long before a token expires that a new one is fetched in the background. Defaults to a
+	// minute.
+	RefreshBefore time.Duration
+	HttpClient    *http.Client
+}
+
+// OAuth2Authenticator adds a bearer token obtained with the OAuth2 client credentials grant to requests
+type OAuth2Authenticator struct {
+	config OAuth2ClientCredentialsConfig
+	cache  *tokenCache
+}
+
+func (oauth2Auth *OAuth2Authenticator) Authenticate(req *http.Request) error {
+	token, err := oauth2Auth.cache.get(req.Context())
+	if err != nil {
+		return err
+	}
+	tokenType := token.tokenType
+	if tokenType == "" || strings.EqualFold(tokenType, "bearer") {
+		tokenType = "Bearer"
+	}
+	req.Header.Set("Authorization", tokenType+" "+token.value)
+	return nil
+}
+
+func (oauth2Auth *OAuth2Authenticator) fetchToken(ctx context.Context) (cachedToken, error) {
+	config := oauth2Auth.config
+
+	form := url.Values{}
+	for key, values := range config.EndpointParams {
+		form[key] = append([]string{}, values...)
+	}
+	form.Set("grant_type", "client_credentials")
+	if len(config.Scopes) > 0 {
+		form.Set("scope", strings.Join(config.Scopes, " "))
+	}
+	if config.Audience != "" {
+		form.Set("audience", config.Audience)
+	}
+	if config.ClientSecretInBody {
+		form.Set("client_id", config.ClientId)
+		form.Set("client_secret", config.ClientSecret)
+	}
+
+	issuedAt := time.Now()
+	tokenResponse, err := requestToken(ctx, config.HttpClient, config.TokenUrl, form, func(req *http.Request) {
+		if !config.ClientSecretInBody {
+			req.SetBasicAuth(url.QueryEscape(config.ClientId), url.QueryEscape(config.ClientSecret))
+		}
+	})
+	if err != nil {
+		return cachedToken{}, err
+	}
+	if tokenResponse.AccessToken == "" {
+		return cachedToken{}, fmt.Errorf("token response from %s has no access token", config.TokenUrl)
+	}
+	return cachedToken{
+		value:     tokenResponse.AccessToken,
+		tokenType: tokenResponse.TokenType,
+		expiresAt: tokenResponse.expiresAt(issuedAt),
+	}, nil
+}
+
+func NewOAuth2ClientCredentialsAuthenticator(config OAuth2ClientCredentialsConfig) *OAuth2Authenticator {
+	oauth2Auth := &OAuth2Authenticator{
+		config: config,
+	}
+	oauth2Auth.cache = newTokenCache(config.RefreshBefore, oauth2Auth.fetchToken)
+	return oauth2Auth
+}
+
+func (resourceManager *ResourceManager) AddOAuth2Auth(config OAuth2ClientCredentialsConfig) {
+	resourceManager.AddAuth("OAuth2", NewOAuth2ClientCredentialsAuthenticator(config))
+}
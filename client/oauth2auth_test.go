@@ -0,0 +1,205 @@
+package mcmaclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+type fakeTokenServer struct {
+	*httptest.Server
+	mutex     sync.Mutex
+	requests  []url.Values
+	expiresIn interface{}
+	delay     time.Duration
+}
+
+func newFakeTokenServer(expiresIn interface{}) *fakeTokenServer {
+	s := &fakeTokenServer{expiresIn: expiresIn}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = r.ParseForm()
+		form := r.PostForm
+		if clientId, clientSecret, ok := r.BasicAuth(); ok {
+			form.Set("basic_client_id", clientId)
+			form.Set("basic_client_secret", clientSecret)
+		}
+		time.Sleep(s.delay)
+
+		s.mutex.Lock()
+		s.requests = append(s.requests, form)
+		count := len(s.requests)
+		s.mutex.Unlock()
+
+		if form.Get("basic_client_secret") == "wrong" || form.Get("client_secret") == "wrong" {
+			writeJson(w, http.StatusUnauthorized, map[string]interface{}{"error": "invalid_client", "error_description": "bad secret"})
+			return
+		}
+		writeJson(w, http.StatusOK, map[string]interface{}{
+			"access_token": fmt.Sprintf("token-%d", count),
+			"token_type":   "bearer",
+			"expires_in":   s.expiresIn,
+		})
+	}))
+	return s
+}
+
+func (s *fakeTokenServer) getRequests() []url.Values {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.requests
+}
+
+func authorizationFor(t *testing.T, authenticator Authenticator) string {
+	req, _ := http.NewRequest("GET", "https://service.example.com/jobs", nil)
+	if err := authenticator.Authenticate(req); err != nil {
+		t.Fatalf("%v", err)
+	}
+	return req.Header.Get("Authorization")
+}
+
+func TestOAuth2AuthenticatorCachesTokenAcrossConcurrentCalls(t *testing.T) {
+	server := newFakeTokenServer(3600)
+	defer server.Close()
+	server.delay = 20 * time.Millisecond
+
+	authenticator := NewOAuth2ClientCredentialsAuthenticator(OAuth2ClientCredentialsConfig{
+		TokenUrl:     server.URL,
+		ClientId:     "client id",
+		ClientSecret: "secret",
+		Scopes:       []string{"jobs.read", "jobs.write"},
+		Audience:     "https://mcma.example.com",
+	})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if authorization := authorizationFor(t, authenticator); authorization != "Bearer token-1" {
+				t.Errorf("expected the cached token, got %q", authorization)
+			}
+		}()
+	}
+	wg.Wait()
+
+	requests := server.getRequests()
+	if len(requests) != 1 {
+		t.Fatalf("expected 1 token request, got %d", len(requests))
+	}
+	form := requests[0]
+	if form.Get("grant_type") != "client_credentials" || form.Get("scope") != "jobs.read jobs.write" || form.Get("audience") != "https://mcma.example.com" {
+		t.Errorf("unexpected token request %v", form)
+	}
+	if form.Get("basic_client_id") != "client+id" || form.Get("basic_client_secret") != "secret" || form.Get("client_secret") != "" {
+		t.Errorf("expected the client credentials in basic auth, got %v", form)
+	}
+}
+
+func TestOAuth2AuthenticatorRefreshesBeforeExpiry(t *testing.T) {
+	server := newFakeTokenServer("2")
+	defer server.Close()
+
+	authenticator := NewOAuth2ClientCredentialsAuthenticator(OAuth2ClientCredentialsConfig{
+		TokenUrl:           server.URL,
+		ClientId:           "client",
+		ClientSecret:       "secret",
+		ClientSecretInBody: true,
+		RefreshBefore:      time.Minute,
+	})
+
+	if authorization := authorizationFor(t, authenticator); authorization != "Bearer token-1" {
+		t.Fatalf("expected the first token, got %q", authorization)
+	}
+	// the refresh window is capped at half the 2 second lifetime
+	time.Sleep(1100 * time.Millisecond)
+	// the token is within the refresh window, so it is still used while a new one is fetched
+	if authorization := authorizationFor(t, authenticator); authorization != "Bearer token-1" {
+		t.Fatalf("expected the first token while refreshing, got %q", authorization)
+	}
+	waitFor(t, func() bool { return len(server.getRequests()) == 2 })
+	if authorization := authorizationFor(t, authenticator); authorization != "Bearer token-2" {
+		t.Fatalf("expected the refreshed token, got %q", authorization)
+	}
+
+	if form := server.getRequests()[0]; form.Get("client_id") != "client" || form.Get("client_secret") != "secret" {
+		t.Errorf("expected the client credentials in the body, got %v", form)
+	}
+}
+
+func TestOAuth2AuthenticatorDoesNotRefreshShortLivedTokensOnEveryUse(t *testing.T) {
+	server := newFakeTokenServer(30)
+	defer server.Close()
+
+	authenticator := NewOAuth2ClientCredentialsAuthenticator(OAuth2ClientCredentialsConfig{
+		TokenUrl:      server.URL,
+		ClientId:      "client",
+		ClientSecret:  "secret",
+		RefreshBefore: time.Minute,
+	})
+
+	for i := 0; i < 10; i++ {
+		if authorization := authorizationFor(t, authenticator); authorization != "Bearer token-1" {
+			t.Fatalf("expected the cached token, got %q", authorization)
+		}
+	}
+	time.Sleep(50 * time.Millisecond)
+	if requests := server.getRequests(); len(requests) != 1 {
+		t.Fatalf("expected 1 token request for a token with a lifetime shorter than the refresh window, got %d", len(requests))
+	}
+}
+
+func TestOAuth2AuthenticatorStopsWaitingWhenContextIsDone(t *testing.T) {
+	server := newFakeTokenServer(3600)
+	defer server.Close()
+	server.delay = 500 * time.Millisecond
+
+	authenticator := NewOAuth2ClientCredentialsAuthenticator(OAuth2ClientCredentialsConfig{
+		TokenUrl:     server.URL,
+		ClientId:     "client",
+		ClientSecret: "secret",
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	req, _ := http.NewRequestWithContext(ctx, "GET", "https://service.example.com/jobs", nil)
+	start := time.Now()
+	if err := authenticator.Authenticate(req); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected the context deadline to be reported, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 250*time.Millisecond {
+		t.Fatalf("expected the caller to stop waiting at its deadline, waited %v", elapsed)
+	}
+
+	// the shared fetch carries on and is used by later callers
+	if authorization := authorizationFor(t, authenticator); authorization != "Bearer token-1" {
+		t.Fatalf("expected the token from the shared fetch, got %q", authorization)
+	}
+	if requests := server.getRequests(); len(requests) != 1 {
+		t.Fatalf("expected 1 token request, got %d", len(requests))
+	}
+}
+
+func TestOAuth2AuthenticatorReportsTokenErrors(t *testing.T) {
+	server := newFakeTokenServer(3600)
+	defer server.Close()
+
+	resourceManager := NewResourceManagerWithOptions(server.URL)
+	resourceManager.AddOAuth2Auth(OAuth2ClientCredentialsConfig{TokenUrl: server.URL, ClientId: "client", ClientSecret: "wrong"})
+	authenticator, err := resourceManager.authProvider.Get("OAuth2")
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	req, _ := http.NewRequest("GET", "https://service.example.com/jobs", nil)
+	err = authenticator.Authenticate(req)
+	if err == nil || !strings.Contains(err.Error(), "invalid_client: bad secret") {
+		t.Errorf("expected the token error, got %v", err)
+	}
+}
@@ -0,0 +1,114 @@
+package mcmaclient
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/ebu/mcma-libraries-go/model"
+)
+
+// Option configures a ResourceManager created with NewResourceManagerWithOptions
+type Option func(resourceManager *ResourceManager)
+
+// WithHttpClient sets the http client used for every request. A nil client is ignored and the default client is kept.
+func WithHttpClient(httpClient *http.Client) Option {
+	return func(resourceManager *ResourceManager) {
+		if httpClient != nil {
+			resourceManager.httpClient = httpClient
+		}
+	}
+}
+
+// WithRetryOptions sets the retry options used by every call that does not take its own retry options
+func WithRetryOptions(retryOptions RetryOptions) Option {
+	return func(resourceManager *ResourceManager) {
+		resourceManager.retryOptions = &retryOptions
+	}
+}
+
+func WithTracker(tracker *model.McmaTracker) Option {
+	return func(resourceManager *ResourceManager) {
+		resourceManager.tracker = tracker
+	}
+}
+
+func WithServiceRegistryAuthType(authType string) Option {
+	return func(resourceManager *ResourceManager) {
+		resourceManager.serviceRegistryAuthType = authType
+	}
+}
+
+func WithAuthenticator(authType string, authenticator Authenticator) Option {
+	return func(resourceManager *ResourceManager) {
+		resourceManager.authProvider.Add(authType, authenticator)
+	}
+}
+
+func WithLogger(logger Logger) Option {
+	return func(resourceManager *ResourceManager) {
+		resourceManager.logger = logger
+	}
+}
+
+// WithRegistryCacheTtl is the option equivalent of SetRegistryCacheTtl
+func WithRegistryCacheTtl(ttl time.Duration) Option {
+	return func(resourceManager *ResourceManager) {
+		resourceManager.registryCacheTtl = ttl
+	}
+}
+
+// WithBackgroundRefresh starts refreshing the services from the service registry every interval as soon as the
+// resource manager is created. Call StopBackgroundRefresh to stop it.
+func WithBackgroundRefresh(interval time.Duration) Option {
+	return func(resourceManager *ResourceManager) {
+		resourceManager.backgroundRefreshInterval = interval
+	}
+}
+
+func WithServiceSnapshotStore(snapshotStore ServiceSnapshotStore) Option {
+	return func(resourceManager *ResourceManager) {
+		resourceManager.snapshotStore = snapshotStore
+	}
+}
+
+func WithQueryConcurrency(queryConcurrency int) Option {
+	return func(resourceManager *ResourceManager) {
+		resourceManager.queryConcurrency = queryConcurrency
+	}
+}
+
+// WithServices is the option equivalent of SetStaticServices
+func WithServices(services []model.Service) Option {
+	return func(resourceManager *ResourceManager) {
+		resourceManager.staticServices = append([]model.Service{}, services...)
+	}
+}
+
+// WithMiddleware is the option equivalent of Use
+func WithMiddleware(middlewares ...Middleware) Option {
+	return func(resourceManager *ResourceManager) {
+		resourceManager.middlewares = append(resourceManager.middlewares, middlewares...)
+	}
+}
+
+// WithUserAgent sets the User-Agent header on requests that do not already have one
+func WithUserAgent(userAgent string) Option {
+	return func(resourceManager *ResourceManager) {
+		resourceManager.userAgent = userAgent
+	}
+}
+
+func NewResourceManagerWithOptions(serviceRegistryUrl string, opts ...Option) *ResourceManager {
+	resourceManager := &ResourceManager{
+		authProvider:       newAuthProvider(),
+		httpClient:         &http.Client{},
+		serviceRegistryUrl: serviceRegistryUrl,
+	}
+	for _, opt := range opts {
+		opt(resourceManager)
+	}
+	if resourceManager.backgroundRefreshInterval > 0 {
+		resourceManager.StartBackgroundRefresh(resourceManager.backgroundRefreshInterval)
+	}
+	return resourceManager
+}
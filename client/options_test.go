@@ -0,0 +1,128 @@
+package mcmaclient
+
+import (
+	"fmt"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ebu/mcma-libraries-go/model"
+)
+
+type recordingLogger struct {
+	mutex    sync.Mutex
+	messages []string
+}
+
+func (l *recordingLogger) Printf(format string, v ...interface{}) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	l.messages = append(l.messages, fmt.Sprintf(format, v...))
+}
+
+func (l *recordingLogger) count() int {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	return len(l.messages)
+}
+
+type headerAuthenticator struct {
+	header string
+	value  string
+}
+
+func (a headerAuthenticator) Authenticate(req *http.Request) error {
+	req.Header.Set(a.header, a.value)
+	return nil
+}
+
+func TestNewResourceManagerWithOptions(t *testing.T) {
+	server := newFakeMcmaServer()
+	defer server.Close()
+	server.addService("job-processor", map[string]string{"Job": "/jobs"})
+
+	var mutex sync.Mutex
+	var headers http.Header
+	attempts := 0
+	server.handle("GET", "/jobs", func(w http.ResponseWriter, r *http.Request) {
+		mutex.Lock()
+		defer mutex.Unlock()
+		headers = r.Header.Clone()
+		attempts++
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+
+	resourceManager := NewResourceManagerWithOptions(server.URL,
+		WithHttpClient(server.Client()),
+		WithRetryOptions(RetryOptions{ShouldRetry: DefaultShouldRetry, Intervals: []time.Duration{time.Millisecond}}),
+		WithTracker(&model.McmaTracker{Id: "tracker-1", Label: "test"}),
+		WithUserAgent("mcma-test/1.0"),
+	)
+
+	// the configured retry options apply to calls that do not take their own
+	if _, err := Query[model.Job](resourceManager, nil); err == nil {
+		t.Fatal("expected an error")
+	}
+	mutex.Lock()
+	defer mutex.Unlock()
+	if attempts != 2 {
+		t.Errorf("expected 2 attempts, got %d", attempts)
+	}
+	if headers.Get("User-Agent") != "mcma-test/1.0" {
+		t.Errorf("expected user agent to be set, got %q", headers.Get("User-Agent"))
+	}
+	if headers.Get("mcma-tracker") == "" {
+		t.Error("expected tracker header to be set")
+	}
+}
+
+func TestNewResourceManagerWithOptionsAuthenticator(t *testing.T) {
+	server := newFakeMcmaServer()
+	defer server.Close()
+	service := server.addService("job-processor", map[string]string{"Job": "/jobs"})
+	service.AuthType = "Test"
+
+	var authorization string
+	server.handle("GET", "/jobs", func(w http.ResponseWriter, r *http.Request) {
+		authorization = r.Header.Get("Authorization")
+		writeJson(w, http.StatusOK, map[string]interface{}{"results": []interface{}{}})
+	})
+
+	resourceManager := NewResourceManagerFromServices([]model.Service{service},
+		WithAuthenticator("Test", headerAuthenticator{header: "Authorization", value: "Bearer token"}))
+	if _, err := Query[model.Job](resourceManager, nil); err != nil {
+		t.Fatalf("%v", err)
+	}
+	if authorization != "Bearer token" {
+		t.Errorf("expected authenticator to be used, got %q", authorization)
+	}
+}
+
+func TestNewResourceManagerWithOptionsLogsBackgroundRefreshFailures(t *testing.T) {
+	server := newFakeMcmaServer()
+	defer server.Close()
+	server.handle("GET", "/services", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+	})
+
+	logger := &recordingLogger{}
+	resourceManager := NewResourceManagerWithOptions(server.URL,
+		WithLogger(logger),
+		WithBackgroundRefresh(10*time.Millisecond),
+	)
+	defer resourceManager.StopBackgroundRefresh()
+
+	waitFor(t, func() bool { return logger.count() > 0 })
+}
+
+func TestNewResourceManagerWithOptionsIgnoresNilHttpClient(t *testing.T) {
+	resourceManager := NewResourceManagerWithOptions("https://service-registry", WithHttpClient(nil))
+	if resourceManager.httpClient == nil {
+		t.Fatal("expected the default http client to be kept")
+	}
+	resourceManager.SetHttpClient(nil)
+	if resourceManager.httpClient == nil {
+		t.Fatal("expected SetHttpClient to ignore a nil client")
+	}
+}
@@ -0,0 +1,131 @@
+package mcmaclient
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+const DefaultQueryConcurrency = 4
+
+type EndpointQueryError struct {
+	HttpEndpoint string
+	Err          error
+}
+
+func (endpointQueryError EndpointQueryError) Error() string {
+	return fmt.Sprintf("%s: %v", endpointQueryError.HttpEndpoint, endpointQueryError.Err)
+}
+
+func (endpointQueryError EndpointQueryError) Unwrap() error {
+	return endpointQueryError.Err
+}
+
+// QueryError is returned by the resource manager's queries when one or more of the queried resource endpoints failed.
+// The generic Query and QueryContext still return the results from the endpoints that succeeded alongside the error,
+// while ResourceManager.Query and QueryResources return no results.
+type QueryError struct {
+	ResourceType string
+	Succeeded    []string
+	Failed       []EndpointQueryError
+}
+
+func (queryError *QueryError) Error() string {
+	var errs []string
+	for _, failed := range queryError.Failed {
+		errs = append(errs, failed.Error())
+	}
+	return fmt.Sprintf("query for resource type '%s' failed on %d of %d endpoints:\n%s", queryError.ResourceType, len(queryError.Failed), len(queryError.Failed)+len(queryError.Succeeded), strings.Join(errs, "\n"))
+}
+
+func (queryError *QueryError) Unwrap() []error {
+	var errs []error
+	for _, failed := range queryError.Failed {
+		errs = append(errs, failed)
+	}
+	return errs
+}
+
+func (queryError *QueryError) IsPartial() bool {
+	return len(queryError.Succeeded) > 0
+}
+
+func (resourceManager *ResourceManager) SetQueryConcurrency(queryConcurrency int) {
+	resourceManager.servicesMutex.Lock()
+	defer resourceManager.servicesMutex.Unlock()
+	resourceManager.queryConcurrency = queryConcurrency
+}
+
+func (resourceManager *ResourceManager) getQueryConcurrency() int {
+	resourceManager.servicesMutex.RLock()
+	defer resourceManager.servicesMutex.RUnlock()
+	if resourceManager.queryConcurrency <= 0 {
+		return DefaultQueryConcurrency
+	}
+	return resourceManager.queryConcurrency
+}
+
+func (resourceManager *ResourceManager) queryResourceEndpoints(
+	ctx context.Context,
+	resourceType string,
+	getResourceEndpointClient func(serviceClient *ServiceClient) (*ResourceEndpointClient, bool),
+	query func(ctx context.Context, resourceEndpointClient *ResourceEndpointClient) ([]interface{}, error),
+) ([]interface{}, error) {
+	if err := resourceManager.EnsureInitContext(ctx); err != nil {
+		return nil, err
+	}
+
+	usedHttpEndpoints := make(map[string]struct{})
+	var resourceEndpointClients []*ResourceEndpointClient
+	for _, s := range resourceManager.getServices() {
+		if resourceEndpointClient, matched := getResourceEndpointClient(s); matched {
+			if _, alreadyUsed := usedHttpEndpoints[resourceEndpointClient.getHttpEndpoint()]; alreadyUsed {
+				continue
+			}
+			usedHttpEndpoints[resourceEndpointClient.getHttpEndpoint()] = struct{}{}
+			resourceEndpointClients = append(resourceEndpointClients, resourceEndpointClient)
+		}
+	}
+	if len(resourceEndpointClients) == 0 {
+		return nil, fmt.Errorf("no available resource endpoints for resource of type '%s'", resourceType)
+	}
+
+	endpointResults := make([][]interface{}, len(resourceEndpointClients))
+	endpointErrs := make([]error, len(resourceEndpointClients))
+	semaphore := make(chan struct{}, resourceManager.getQueryConcurrency())
+	var wg sync.WaitGroup
+	for i, resourceEndpointClient := range resourceEndpointClients {
+		wg.Add(1)
+		go func(i int, resourceEndpointClient *ResourceEndpointClient) {
+			defer wg.Done()
+			select {
+			case semaphore <- struct{}{}:
+				defer func() { <-semaphore }()
+			case <-ctx.Done():
+				endpointErrs[i] = ctx.Err()
+				return
+			}
+			endpointResults[i], endpointErrs[i] = query(ctx, resourceEndpointClient)
+		}(i, resourceEndpointClient)
+	}
+	wg.Wait()
+
+	var results []interface{}
+	queryError := &QueryError{ResourceType: resourceType}
+	for i, resourceEndpointClient := range resourceEndpointClients {
+		if endpointErrs[i] != nil {
+			queryError.Failed = append(queryError.Failed, EndpointQueryError{
+				HttpEndpoint: resourceEndpointClient.getHttpEndpoint(),
+				Err:          endpointErrs[i],
+			})
+			continue
+		}
+		queryError.Succeeded = append(queryError.Succeeded, resourceEndpointClient.getHttpEndpoint())
+		results = append(results, endpointResults[i]...)
+	}
+	if len(queryError.Failed) > 0 {
+		return results, queryError
+	}
+	return results, nil
+}
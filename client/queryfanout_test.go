@@ -0,0 +1,104 @@
+package mcmaclient
+
+import (
+	"errors"
+	"net/http"
+	"reflect"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ebu/mcma-libraries-go/model"
+)
+
+func TestQueryFansOutWithConcurrencyLimitAndReportsPartialFailures(t *testing.T) {
+	server := newFakeMcmaServer()
+	defer server.Close()
+
+	var inFlight, maxInFlight int32
+	for i := 0; i < 4; i++ {
+		path := "/job-assignments-" + strconv.Itoa(i)
+		server.addService("service-"+strconv.Itoa(i), map[string]string{"JobAssignment": path})
+		failing := i == 2
+		server.handle("GET", path, func(w http.ResponseWriter, r *http.Request) {
+			current := atomic.AddInt32(&inFlight, 1)
+			defer atomic.AddInt32(&inFlight, -1)
+			for {
+				observed := atomic.LoadInt32(&maxInFlight)
+				if current <= observed || atomic.CompareAndSwapInt32(&maxInFlight, observed, current) {
+					break
+				}
+			}
+			time.Sleep(50 * time.Millisecond)
+			if failing {
+				writeJson(w, http.StatusBadRequest, model.NewProblemDetail("uri://bad-request", "Bad Request", "unsupported filter"))
+				return
+			}
+			jobAssignment := model.NewJobAssignment(server.URL + "/jobs/1")
+			jobAssignment.Id = server.URL + r.URL.Path + "/1"
+			writeJson(w, http.StatusOK, model.QueryResults{Results: []interface{}{jobAssignment}})
+		})
+	}
+
+	resourceManager := NewResourceManagerWithOptions(server.URL)
+	resourceManager.SetQueryConcurrency(2)
+
+	results, err := Query[model.JobAssignment](resourceManager, nil)
+	if len(results) != 3 {
+		t.Errorf("expected results from the 3 healthy endpoints, got %d", len(results))
+	}
+
+	var queryError *QueryError
+	if !errors.As(err, &queryError) {
+		t.Fatalf("expected *QueryError, got %v", err)
+	}
+	if !queryError.IsPartial() || len(queryError.Succeeded) != 3 || len(queryError.Failed) != 1 {
+		t.Errorf("unexpected query error %+v", queryError)
+	}
+	if queryError.Failed[0].HttpEndpoint != server.URL+"/job-assignments-2" {
+		t.Errorf("unexpected failed endpoint %s", queryError.Failed[0].HttpEndpoint)
+	}
+	var httpError *HttpError
+	if !errors.As(err, &httpError) || httpError.StatusCode != http.StatusBadRequest {
+		t.Errorf("expected the endpoint's HttpError to be reachable, got %v", err)
+	}
+
+	if maxInFlight != 2 {
+		t.Errorf("expected at most 2 concurrent queries, observed %d", maxInFlight)
+	}
+
+	// the reflection based queries keep returning no results when any endpoint fails
+	legacyResults, err := resourceManager.Query(reflect.TypeOf(model.JobAssignment{}), nil)
+	if legacyResults != nil || !errors.As(err, &queryError) {
+		t.Errorf("expected no results and a *QueryError, got %d results and %v", len(legacyResults), err)
+	}
+	legacyResults, err = resourceManager.QueryResources("JobAssignment", nil)
+	if legacyResults != nil || !errors.As(err, &queryError) {
+		t.Errorf("expected no results and a *QueryError, got %d results and %v", len(legacyResults), err)
+	}
+}
+
+func TestSetQueryConcurrencyWhileQuerying(t *testing.T) {
+	server := newFakeMcmaServer()
+	defer server.Close()
+	server.addService("service", map[string]string{"JobAssignment": "/job-assignments"})
+	server.handle("GET", "/job-assignments", func(w http.ResponseWriter, r *http.Request) {
+		writeJson(w, http.StatusOK, model.QueryResults{Results: []interface{}{}})
+	})
+
+	resourceManager := NewResourceManagerWithOptions(server.URL)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 10; i++ {
+			if _, err := Query[model.JobAssignment](resourceManager, nil); err != nil {
+				t.Errorf("%v", err)
+			}
+		}
+	}()
+	for i := 1; i <= 10; i++ {
+		resourceManager.SetQueryConcurrency(i)
+	}
+	<-done
+}
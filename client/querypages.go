@@ -0,0 +1,169 @@
+package mcmaclient
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	"github.com/ebu/mcma-libraries-go/model"
+)
+
+// QueryPageIterator follows nextPageStartToken from page to page of a query. A nil type yields results as maps.
+type QueryPageIterator struct {
+	query           func(ctx context.Context, queryParameters *QueryParameters) (model.QueryResults, error)
+	source          string
+	queryParameters *QueryParameters
+	maxItems        int
+	pageStartToken  string
+	itemCount       int
+	done            bool
+}
+
+func newQueryPageIterator(source string, queryParameters *QueryParameters, maxItems int, query func(ctx context.Context, queryParameters *QueryParameters) (model.QueryResults, error)) *QueryPageIterator {
+	var pageStartToken string
+	if queryParameters != nil {
+		pageStartToken = queryParameters.values.Get(pageStartTokenParameter)
+	}
+	return &QueryPageIterator{
+		query:           query,
+		source:          source,
+		queryParameters: queryParameters,
+		maxItems:        maxItems,
+		pageStartToken:  pageStartToken,
+	}
+}
+
+func (resourceEndpointClient *ResourceEndpointClient) NewQueryPageIterator(t reflect.Type, url string, queryParameters *QueryParameters, maxItems int) *QueryPageIterator {
+	return resourceEndpointClient.NewQueryPageIteratorWithRetries(t, url, queryParameters, maxItems, resourceEndpointClient.getRetryOptions())
+}
+
+func (resourceEndpointClient *ResourceEndpointClient) NewQueryPageIteratorWithRetries(t reflect.Type, url string, queryParameters *QueryParameters, maxItems int, retryOpts RetryOptions) *QueryPageIterator {
+	return newQueryPageIterator(resourceEndpointClient.getHttpEndpoint(), queryParameters, maxItems, func(ctx context.Context, queryParameters *QueryParameters) (model.QueryResults, error) {
+		if t == nil {
+			return resourceEndpointClient.QueryMapsWithRetriesContext(ctx, url, queryParameters, retryOpts)
+		}
+		return resourceEndpointClient.QueryWithRetriesContext(ctx, t, url, queryParameters, retryOpts)
+	})
+}
+
+// newQueryPageIterator pages through the query at the absolute url, for urls that are not on a resource endpoint
+func (client *McmaHttpClient) newQueryPageIterator(t reflect.Type, url string, queryParameters *QueryParameters, maxItems int, retryOpts RetryOptions) *QueryPageIterator {
+	return newQueryPageIterator(url, queryParameters, maxItems, func(ctx context.Context, queryParameters *QueryParameters) (model.QueryResults, error) {
+		queryResults, err := client.queryMapsWithRetriesContext(ctx, url, queryParameters, retryOpts)
+		if err != nil || t == nil {
+			return queryResults, err
+		}
+		return getTypedQueryResults(queryResults, t, url)
+	})
+}
+
+func (client *McmaHttpClient) queryMapsWithRetriesContext(ctx context.Context, url string, queryParameters *QueryParameters, retryOpts RetryOptions) (model.QueryResults, error) {
+	var queryResults model.QueryResults
+	url, err := queryParameters.appendToUrl(url)
+	if err != nil {
+		return queryResults, err
+	}
+
+	getResp, err := client.GetWithRetriesContext(ctx, url, true, retryOpts)
+	if err != nil {
+		return queryResults, fmt.Errorf("failed to query %v: %w", url, err)
+	}
+
+	body, err := readJsonRespBody(getResp, reflect.TypeOf(queryResults))
+	if err != nil {
+		return queryResults, fmt.Errorf("failed to get query results for %v: %v", url, err)
+	}
+
+	return body.(model.QueryResults), err
+}
+
+func getTypedQueryResults(queryResults model.QueryResults, t reflect.Type, url string) (model.QueryResults, error) {
+	results, err := queryResults.GetResults(t)
+	if err != nil {
+		return queryResults, fmt.Errorf("failed to get typed query results for %v: %v", url, err)
+	}
+	queryResults.Results = results
+	return queryResults, nil
+}
+
+func (iterator *QueryPageIterator) HasNext() bool {
+	return !iterator.done
+}
+
+func (iterator *QueryPageIterator) Next(ctx context.Context) (model.QueryResults, error) {
+	if iterator.done {
+		return model.QueryResults{}, fmt.Errorf("no more pages")
+	}
+	if err := ctx.Err(); err != nil {
+		return model.QueryResults{}, err
+	}
+
+	queryParameters := iterator.queryParameters.Clone().WithPageStartToken(iterator.pageStartToken)
+
+	page, err := iterator.query(ctx, queryParameters)
+	if err != nil {
+		return page, err
+	}
+
+	if iterator.maxItems > 0 && iterator.itemCount+len(page.Results) >= iterator.maxItems {
+		page.Results = page.Results[:iterator.maxItems-iterator.itemCount]
+		iterator.done = true
+	}
+	iterator.itemCount += len(page.Results)
+
+	if page.NextPageStartToken == "" {
+		iterator.done = true
+	} else if page.NextPageStartToken == iterator.pageStartToken {
+		iterator.done = true
+		return page, fmt.Errorf("query of %s returned the same page start token '%s' twice", iterator.source, page.NextPageStartToken)
+	}
+	iterator.pageStartToken = page.NextPageStartToken
+
+	return page, nil
+}
+
+func (resourceEndpointClient *ResourceEndpointClient) QueryEach(ctx context.Context, t reflect.Type, url string, queryParameters *QueryParameters, maxItems int, yield func(interface{}) bool) error {
+	return resourceEndpointClient.QueryEachWithRetries(ctx, t, url, queryParameters, maxItems, resourceEndpointClient.getRetryOptions(), yield)
+}
+
+// QueryEachWithRetries calls yield for every result on every page until the pages or maxItems are exhausted, or
+// yield returns false. A maxItems of zero means no limit.
+func (resourceEndpointClient *ResourceEndpointClient) QueryEachWithRetries(ctx context.Context, t reflect.Type, url string, queryParameters *QueryParameters, maxItems int, retryOpts RetryOptions, yield func(interface{}) bool) error {
+	iterator := resourceEndpointClient.NewQueryPageIteratorWithRetries(t, url, queryParameters, maxItems, retryOpts)
+	for iterator.HasNext() {
+		page, err := iterator.Next(ctx)
+		if err != nil {
+			return err
+		}
+		for _, r := range page.Results {
+			if !yield(r) {
+				return nil
+			}
+		}
+	}
+	return nil
+}
+
+func (resourceEndpointClient *ResourceEndpointClient) QueryAll(ctx context.Context, t reflect.Type, url string, queryParameters *QueryParameters, maxItems int) ([]interface{}, error) {
+	return resourceEndpointClient.QueryAllWithRetries(ctx, t, url, queryParameters, maxItems, resourceEndpointClient.getRetryOptions())
+}
+
+func (resourceEndpointClient *ResourceEndpointClient) QueryAllWithRetries(ctx context.Context, t reflect.Type, url string, queryParameters *QueryParameters, maxItems int, retryOpts RetryOptions) ([]interface{}, error) {
+	var results []interface{}
+	err := resourceEndpointClient.QueryEachWithRetries(ctx, t, url, queryParameters, maxItems, retryOpts, func(r interface{}) bool {
+		results = append(results, r)
+		return true
+	})
+	return results, err
+}
+
+func QueryEndpointEach[T any](ctx context.Context, resourceEndpointClient *ResourceEndpointClient, url string, queryParameters *QueryParameters, maxItems int, yield func(T) bool) error {
+	return resourceEndpointClient.QueryEach(ctx, typeOf[T](), url, queryParameters, maxItems, func(r interface{}) bool {
+		return yield(castResult[T](r))
+	})
+}
+
+func QueryEndpointAll[T any](ctx context.Context, resourceEndpointClient *ResourceEndpointClient, url string, queryParameters *QueryParameters, maxItems int) ([]T, error) {
+	results, err := resourceEndpointClient.QueryAll(ctx, typeOf[T](), url, queryParameters, maxItems)
+	return castResults[T](results), err
+}
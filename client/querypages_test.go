@@ -0,0 +1,95 @@
+package mcmaclient
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"reflect"
+	"strconv"
+	"testing"
+
+	"github.com/ebu/mcma-libraries-go/model"
+)
+
+// servePagedServices serves the registered services in pages of pageSize using the index of the next item as token
+func servePagedServices(server *fakeMcmaServer, pageSize int) {
+	server.handle("GET", "/services", func(w http.ResponseWriter, r *http.Request) {
+		server.mutex.Lock()
+		services := append([]model.Service(nil), server.services...)
+		server.mutex.Unlock()
+
+		start, _ := strconv.Atoi(r.URL.Query().Get("pageStartToken"))
+		end := start + pageSize
+		var queryResults model.QueryResults
+		if end < len(services) {
+			queryResults.NextPageStartToken = strconv.Itoa(end)
+		} else {
+			end = len(services)
+		}
+		for _, service := range services[start:end] {
+			queryResults.Results = append(queryResults.Results, service)
+		}
+		writeJson(w, http.StatusOK, queryResults)
+	})
+}
+
+func TestInitDiscoversServicesOnAllPages(t *testing.T) {
+	server := newFakeMcmaServer()
+	defer server.Close()
+	for i := 0; i < 5; i++ {
+		server.addService("service-"+strconv.Itoa(i), map[string]string{"Resource" + strconv.Itoa(i): "/resources-" + strconv.Itoa(i)})
+	}
+	servePagedServices(server, 2)
+
+	resourceManager := NewResourceManagerWithOptions(server.URL)
+	if err := resourceManager.Init(); err != nil {
+		t.Fatalf("%v", err)
+	}
+	// the registry itself plus the five registered services
+	if len(resourceManager.getServices()) != 6 {
+		t.Errorf("expected 6 services, got %d", len(resourceManager.getServices()))
+	}
+	if server.countRequests("GET /services") != 3 {
+		t.Errorf("expected 3 page requests, got %v", server.getRequests())
+	}
+}
+
+func TestQueryEachRespectsMaxItemsAndCancellation(t *testing.T) {
+	server := newFakeMcmaServer()
+	defer server.Close()
+	for i := 0; i < 5; i++ {
+		server.addService("service-"+strconv.Itoa(i), nil)
+	}
+	servePagedServices(server, 2)
+
+	resourceEndpointClient := &ResourceEndpointClient{
+		httpClient:       server.Client(),
+		resourceEndpoint: model.NewResourceEndpoint("Service", server.URL+"/services"),
+	}
+
+	services, err := QueryEndpointAll[model.Service](context.Background(), resourceEndpointClient, "", nil, 3)
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	if len(services) != 3 || services[2].Name != "service-2" {
+		t.Errorf("unexpected services %+v", services)
+	}
+
+	var names []string
+	err = QueryEndpointEach(context.Background(), resourceEndpointClient, "", nil, 0, func(service model.Service) bool {
+		names = append(names, service.Name)
+		return len(names) < 4
+	})
+	if err != nil || len(names) != 4 {
+		t.Errorf("expected iteration to stop after 4 items, got %v, %v", names, err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	err = resourceEndpointClient.QueryEach(ctx, reflect.TypeOf(model.Service{}), "", nil, 0, func(interface{}) bool {
+		cancel()
+		return true
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+}
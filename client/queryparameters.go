@@ -0,0 +1,137 @@
+package mcmaclient
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/ebu/mcma-libraries-go/model"
+)
+
+type SortOrder string
+
+const (
+	SortOrderAscending  SortOrder = "asc"
+	SortOrderDescending SortOrder = "desc"
+)
+
+const (
+	pageSizeParameter       = "pageSize"
+	pageStartTokenParameter = "pageStartToken"
+	sortByParameter         = "sortBy"
+	sortOrderParameter      = "sortOrder"
+	filterParameter         = "filter"
+)
+
+// QueryParameters builds the query string for MCMA resource queries. A nil or zero *QueryParameters is a valid empty
+// query, and calling a builder method on a nil *QueryParameters returns a new builder.
+type QueryParameters struct {
+	values url.Values
+	err    error
+}
+
+func NewQueryParameters() *QueryParameters {
+	return &QueryParameters{values: url.Values{}}
+}
+
+// ensureValues returns the builder with its values allocated, allocating a new builder for a nil receiver
+func (queryParameters *QueryParameters) ensureValues() *QueryParameters {
+	if queryParameters == nil {
+		return NewQueryParameters()
+	}
+	if queryParameters.values == nil {
+		queryParameters.values = url.Values{}
+	}
+	return queryParameters
+}
+
+// Where adds an equality filter on the given property, replacing any previous filter on the same property
+func (queryParameters *QueryParameters) Where(propertyName string, value string) *QueryParameters {
+	queryParameters = queryParameters.ensureValues()
+	queryParameters.values.Set(propertyName, value)
+	return queryParameters
+}
+
+func (queryParameters *QueryParameters) WithPageSize(pageSize int) *QueryParameters {
+	queryParameters = queryParameters.ensureValues()
+	queryParameters.values.Set(pageSizeParameter, strconv.Itoa(pageSize))
+	return queryParameters
+}
+
+func (queryParameters *QueryParameters) WithPageStartToken(pageStartToken string) *QueryParameters {
+	queryParameters = queryParameters.ensureValues()
+	if pageStartToken == "" {
+		queryParameters.values.Del(pageStartTokenParameter)
+	} else {
+		queryParameters.values.Set(pageStartTokenParameter, pageStartToken)
+	}
+	return queryParameters
+}
+
+func (queryParameters *QueryParameters) WithSort(sortBy string, sortOrder SortOrder) *QueryParameters {
+	queryParameters = queryParameters.ensureValues()
+	queryParameters.values.Set(sortByParameter, sortBy)
+	if sortOrder != "" {
+		queryParameters.values.Set(sortOrderParameter, string(sortOrder))
+	}
+	return queryParameters
+}
+
+// WithFilter sends an MCMA filter expression as JSON in the filter parameter
+func (queryParameters *QueryParameters) WithFilter(filterExpression model.FilterExpression) *QueryParameters {
+	queryParameters = queryParameters.ensureValues()
+	filterJson, err := json.Marshal(filterExpression)
+	if err != nil {
+		queryParameters.err = fmt.Errorf("failed to marshal filter expression: %v", err)
+		return queryParameters
+	}
+	queryParameters.values.Set(filterParameter, string(filterJson))
+	return queryParameters
+}
+
+func (queryParameters *QueryParameters) Values() url.Values {
+	values := url.Values{}
+	if queryParameters == nil {
+		return values
+	}
+	for key, v := range queryParameters.values {
+		values[key] = append([]string(nil), v...)
+	}
+	return values
+}
+
+func (queryParameters *QueryParameters) Clone() *QueryParameters {
+	if queryParameters == nil {
+		return NewQueryParameters()
+	}
+	return &QueryParameters{
+		values: queryParameters.Values(),
+		err:    queryParameters.err,
+	}
+}
+
+func (queryParameters *QueryParameters) Encode() string {
+	if queryParameters == nil {
+		return ""
+	}
+	return queryParameters.values.Encode()
+}
+
+func (queryParameters *QueryParameters) appendToUrl(url string) (string, error) {
+	if queryParameters == nil {
+		return url, nil
+	}
+	if queryParameters.err != nil {
+		return "", queryParameters.err
+	}
+	queryString := queryParameters.Encode()
+	if queryString == "" {
+		return url, nil
+	}
+	if strings.Contains(url, "?") {
+		return url + "&" + queryString, nil
+	}
+	return url + "?" + queryString, nil
+}
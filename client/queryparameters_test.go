@@ -0,0 +1,91 @@
+package mcmaclient
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/ebu/mcma-libraries-go/model"
+)
+
+func TestQueryParametersEncoding(t *testing.T) {
+	server := newFakeMcmaServer()
+	defer server.Close()
+	server.addService("job-processor", map[string]string{"Job": "/jobs"})
+
+	var received map[string][]string
+	server.handle("GET", "/jobs", func(w http.ResponseWriter, r *http.Request) {
+		received = r.URL.Query()
+		writeJson(w, http.StatusOK, model.QueryResults{})
+	})
+
+	resourceManager := NewResourceManagerWithOptions(server.URL)
+	filter := NewQueryParameters().
+		Where("status", "Completed").
+		Where("jobProfileId", "https://registry/job-profiles/a b&c").
+		WithPageSize(25).
+		WithPageStartToken("token/+=").
+		WithSort("dateCreated", SortOrderDescending).
+		WithFilter(model.NewFilterCriteriaGroup(model.LogicalOperatorOr,
+			model.NewFilterCriteria("status", model.FilterOperatorEqual, "Failed"),
+			model.NewFilterCriteria("progress", model.FilterOperatorGreaterThan, 50)))
+
+	if _, err := Query[model.Job](resourceManager, filter); err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	expected := map[string]string{
+		"status":         "Completed",
+		"jobProfileId":   "https://registry/job-profiles/a b&c",
+		"pageSize":       "25",
+		"pageStartToken": "token/+=",
+		"sortBy":         "dateCreated",
+		"sortOrder":      "desc",
+	}
+	for key, value := range expected {
+		if len(received[key]) != 1 || received[key][0] != value {
+			t.Errorf("expected %s=%q, got %q", key, value, received[key])
+		}
+	}
+
+	var filterExpression map[string]interface{}
+	if err := json.Unmarshal([]byte(received["filter"][0]), &filterExpression); err != nil {
+		t.Fatalf("%v", err)
+	}
+	if filterExpression["logicalOperator"] != "||" || len(filterExpression["children"].([]interface{})) != 2 {
+		t.Errorf("unexpected filter expression %v", filterExpression)
+	}
+}
+
+func TestQueryParametersAppendToUrl(t *testing.T) {
+	var nilParameters *QueryParameters
+	if url, err := nilParameters.appendToUrl("https://service/jobs"); err != nil || url != "https://service/jobs" {
+		t.Errorf("unexpected url %s, %v", url, err)
+	}
+	if url, _ := NewQueryParameters().Where("a", "1").appendToUrl("https://service/jobs?b=2"); url != "https://service/jobs?b=2&a=1" {
+		t.Errorf("unexpected url %s", url)
+	}
+
+	original := NewQueryParameters().Where("a", "1")
+	clone := original.Clone().WithPageStartToken("next")
+	if original.Encode() != "a=1" || clone.Encode() != "a=1&pageStartToken=next" {
+		t.Errorf("expected clone to be independent: %s / %s", original.Encode(), clone.Encode())
+	}
+}
+
+func TestQueryParametersZeroAndNilBuilders(t *testing.T) {
+	zero := &QueryParameters{}
+	zero.Where("status", "Completed").WithPageSize(10).WithPageStartToken("next").WithSort("dateCreated", SortOrderDescending)
+	if encoded := zero.Encode(); encoded != "pageSize=10&pageStartToken=next&sortBy=dateCreated&sortOrder=desc&status=Completed" {
+		t.Errorf("unexpected query %s", encoded)
+	}
+	if encoded := (&QueryParameters{}).WithFilter(model.NewFilterCriteria("status", model.FilterOperatorEqual, "Completed")).Encode(); !strings.HasPrefix(encoded, "filter=") {
+		t.Errorf("unexpected query %s", encoded)
+	}
+
+	var nilParameters *QueryParameters
+	if encoded := nilParameters.Where("status", "Completed").Encode(); encoded != "status=Completed" {
+		t.Errorf("expected a new builder from a nil receiver, got %s", encoded)
+	}
+}
@@ -0,0 +1,135 @@
+package mcmaclient
+
+import (
+	"context"
+	"time"
+
+	"github.com/ebu/mcma-libraries-go/model"
+)
+
+// refreshTimeout bounds a refresh. Refreshes hold the init lock, so without a bound a slow service registry would block
+// callers waiting for services to be loaded beyond their own deadlines.
+const refreshTimeout = 30 * time.Second
+
+func (resourceManager *ResourceManager) getServices() []*ServiceClient {
+	services, _ := resourceManager.getServicesAndLoadedAt()
+	return services
+}
+
+func (resourceManager *ResourceManager) getServicesAndLoadedAt() ([]*ServiceClient, time.Time) {
+	resourceManager.servicesMutex.RLock()
+	defer resourceManager.servicesMutex.RUnlock()
+	return resourceManager.services, resourceManager.servicesLoadedAt
+}
+
+func (resourceManager *ResourceManager) setServices(services []model.Service, loadedAt time.Time) {
+	serviceClients := resourceManager.newServiceClients(services)
+
+	resourceManager.servicesMutex.Lock()
+	defer resourceManager.servicesMutex.Unlock()
+	resourceManager.services = serviceClients
+	resourceManager.servicesLoadedAt = loadedAt
+	resourceManager.lastRefreshErr = nil
+}
+
+func (resourceManager *ResourceManager) isStale(loadedAt time.Time) bool {
+	resourceManager.servicesMutex.RLock()
+	defer resourceManager.servicesMutex.RUnlock()
+	return resourceManager.registryCacheTtl > 0 && time.Since(loadedAt) >= resourceManager.registryCacheTtl
+}
+
+// SetRegistryCacheTtl sets how long services loaded from the service registry are used before they are refreshed.
+// Once the ttl has passed the cached services keep being served while a refresh runs in the background. A ttl of
+// zero, the default, keeps the services until Refresh or Invalidate is called.
+func (resourceManager *ResourceManager) SetRegistryCacheTtl(ttl time.Duration) {
+	resourceManager.servicesMutex.Lock()
+	defer resourceManager.servicesMutex.Unlock()
+	resourceManager.registryCacheTtl = ttl
+}
+
+// Refresh reloads the services from the service registry. If the registry cannot be reached the services that are
+// already loaded are kept and the error is returned. A refresh gives up after 30 seconds if ctx has no earlier
+// deadline.
+func (resourceManager *ResourceManager) Refresh(ctx context.Context) error {
+	resourceManager.initMutex.Lock()
+	defer resourceManager.initMutex.Unlock()
+
+	ctx, cancel := context.WithTimeout(ctx, refreshTimeout)
+	defer cancel()
+
+	services, err := resourceManager.queryServices(ctx)
+	if err != nil {
+		resourceManager.servicesMutex.Lock()
+		resourceManager.lastRefreshErr = err
+		resourceManager.servicesMutex.Unlock()
+		return err
+	}
+	resourceManager.setServices(services, time.Now())
+	resourceManager.saveSnapshot(ctx, services)
+	return nil
+}
+
+// Invalidate drops the loaded services so that they are reloaded from the service registry on next use
+func (resourceManager *ResourceManager) Invalidate() {
+	resourceManager.servicesMutex.Lock()
+	defer resourceManager.servicesMutex.Unlock()
+	resourceManager.services = nil
+	resourceManager.servicesLoadedAt = time.Time{}
+}
+
+// LastRefreshError returns the error from the most recent refresh if it failed and stale services are being served
+func (resourceManager *ResourceManager) LastRefreshError() error {
+	resourceManager.servicesMutex.RLock()
+	defer resourceManager.servicesMutex.RUnlock()
+	return resourceManager.lastRefreshErr
+}
+
+func (resourceManager *ResourceManager) refreshInBackground() {
+	if !resourceManager.refreshing.CompareAndSwap(false, true) {
+		return
+	}
+	go func() {
+		defer resourceManager.refreshing.Store(false)
+		if err := resourceManager.Refresh(context.Background()); err != nil {
+			resourceManager.logf("failed to refresh services from service registry: %v", err)
+		}
+	}()
+}
+
+// StartBackgroundRefresh refreshes the services from the service registry every interval until
+// StopBackgroundRefresh is called
+func (resourceManager *ResourceManager) StartBackgroundRefresh(interval time.Duration) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	// the previous refresh is stopped and replaced under one lock so that concurrent calls cannot leave one running
+	resourceManager.servicesMutex.Lock()
+	if resourceManager.backgroundRefreshCancel != nil {
+		resourceManager.backgroundRefreshCancel()
+	}
+	resourceManager.backgroundRefreshCancel = cancel
+	resourceManager.servicesMutex.Unlock()
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if err := resourceManager.Refresh(ctx); err != nil && ctx.Err() == nil {
+					resourceManager.logf("failed to refresh services from service registry: %v", err)
+				}
+			}
+		}
+	}()
+}
+
+func (resourceManager *ResourceManager) StopBackgroundRefresh() {
+	resourceManager.servicesMutex.Lock()
+	defer resourceManager.servicesMutex.Unlock()
+	if resourceManager.backgroundRefreshCancel != nil {
+		resourceManager.backgroundRefreshCancel()
+		resourceManager.backgroundRefreshCancel = nil
+	}
+}
@@ -0,0 +1,117 @@
+package mcmaclient
+
+import (
+	"context"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func waitFor(t *testing.T, condition func() bool) {
+	deadline := time.Now().Add(2 * time.Second)
+	for !condition() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for condition")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestRegistryCacheRefreshesAfterTtl(t *testing.T) {
+	server := newFakeMcmaServer()
+	defer server.Close()
+	server.addService("service-1", map[string]string{"JobAssignment": "/job-assignments-1"})
+
+	resourceManager := NewResourceManagerWithOptions(server.URL)
+	resourceManager.SetRegistryCacheTtl(20 * time.Millisecond)
+	if err := resourceManager.EnsureInit(); err != nil {
+		t.Fatalf("%v", err)
+	}
+	if len(resourceManager.getServices()) != 2 {
+		t.Fatalf("expected 2 services, got %d", len(resourceManager.getServices()))
+	}
+
+	server.addService("service-2", map[string]string{"JobAssignment": "/job-assignments-2"})
+	time.Sleep(30 * time.Millisecond)
+
+	// the stale services are served while the refresh happens in the background
+	if err := resourceManager.EnsureInit(); err != nil {
+		t.Fatalf("%v", err)
+	}
+	waitFor(t, func() bool { return len(resourceManager.getServices()) == 3 })
+}
+
+func TestSetRegistryCacheTtlWhileInUse(t *testing.T) {
+	server := newFakeMcmaServer()
+	defer server.Close()
+	server.addService("service-1", map[string]string{"JobAssignment": "/job-assignments-1"})
+
+	resourceManager := NewResourceManagerWithOptions(server.URL)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 10; i++ {
+			if err := resourceManager.EnsureInit(); err != nil {
+				t.Errorf("%v", err)
+			}
+		}
+	}()
+	for i := 1; i <= 10; i++ {
+		resourceManager.SetRegistryCacheTtl(time.Duration(i) * time.Millisecond)
+	}
+	<-done
+}
+
+func TestRefreshKeepsStaleServicesWhenRegistryIsUnreachable(t *testing.T) {
+	server := newFakeMcmaServer()
+	defer server.Close()
+	server.addService("service-1", map[string]string{"JobAssignment": "/job-assignments-1"})
+
+	resourceManager := NewResourceManagerWithOptions(server.URL)
+	if err := resourceManager.EnsureInit(); err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	server.handle("GET", "/services", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	})
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	if err := resourceManager.Refresh(ctx); err == nil {
+		t.Fatalf("expected refresh to fail")
+	}
+	if len(resourceManager.getServices()) != 2 {
+		t.Errorf("expected stale services to be kept, got %d", len(resourceManager.getServices()))
+	}
+	if resourceManager.LastRefreshError() == nil {
+		t.Errorf("expected last refresh error to be recorded")
+	}
+
+	resourceManager.Invalidate()
+	if len(resourceManager.getServices()) != 0 {
+		t.Errorf("expected services to be dropped")
+	}
+}
+
+func TestBackgroundRefresh(t *testing.T) {
+	server := newFakeMcmaServer()
+	defer server.Close()
+
+	var queries int32
+	server.handle("GET", "/services", func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&queries, 1)
+		writeJson(w, http.StatusOK, map[string]interface{}{"results": []interface{}{}})
+	})
+
+	resourceManager := NewResourceManagerWithOptions(server.URL)
+	resourceManager.StartBackgroundRefresh(10 * time.Millisecond)
+	waitFor(t, func() bool { return atomic.LoadInt32(&queries) >= 3 })
+	resourceManager.StopBackgroundRefresh()
+
+	stoppedAt := atomic.LoadInt32(&queries)
+	time.Sleep(50 * time.Millisecond)
+	if atomic.LoadInt32(&queries) > stoppedAt+1 {
+		t.Errorf("expected background refresh to stop")
+	}
+}
@@ -2,10 +2,12 @@ package mcmaclient
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"net/http"
 	"reflect"
 	"strings"
+	"sync"
 
 	"github.com/ebu/mcma-libraries-go/model"
 )
@@ -16,15 +18,16 @@ type ResourceEndpointClient struct {
 	resourceEndpoint model.ResourceEndpoint
 	serviceAuthType  string
 	tracker          *model.McmaTracker
+	retryOptions     *RetryOptions
+	userAgent        string
+	middlewares      []Middleware
 	mcmaHttpClient   *McmaHttpClient
-}
-
-type QueryParameters = []struct {
-	key   string
-	value string
+	mcmaHttpMutex    sync.Mutex
 }
 
 func (resourceEndpointClient *ResourceEndpointClient) getMcmaHttpClient() (*McmaHttpClient, error) {
+	resourceEndpointClient.mcmaHttpMutex.Lock()
+	defer resourceEndpointClient.mcmaHttpMutex.Unlock()
 	if resourceEndpointClient.mcmaHttpClient != nil {
 		return resourceEndpointClient.mcmaHttpClient, nil
 	}
@@ -47,11 +50,21 @@ func (resourceEndpointClient *ResourceEndpointClient) getMcmaHttpClient() (*Mcma
 		httpClient:    resourceEndpointClient.httpClient,
 		authenticator: &authenticator,
 		tracker:       resourceEndpointClient.tracker,
+		retryOptions:  resourceEndpointClient.retryOptions,
+		userAgent:     resourceEndpointClient.userAgent,
+		middlewares:   resourceEndpointClient.middlewares,
 	}
 
 	return resourceEndpointClient.mcmaHttpClient, nil
 }
 
+func (resourceEndpointClient *ResourceEndpointClient) getRetryOptions() RetryOptions {
+	if resourceEndpointClient.retryOptions != nil {
+		return *resourceEndpointClient.retryOptions
+	}
+	return DefaultRetryOptions
+}
+
 func (resourceEndpointClient *ResourceEndpointClient) getFullUrl(url string) (string, error) {
 	if url == "" {
 		return resourceEndpointClient.resourceEndpoint.HttpEndpoint, nil
@@ -76,7 +89,7 @@ func (resourceEndpointClient *ResourceEndpointClient) getHttpEndpoint() string {
 	return resourceEndpointClient.resourceEndpoint.HttpEndpoint
 }
 
-func (resourceEndpointClient *ResourceEndpointClient) execute(t reflect.Type, url string, body interface{}, execute func(mcmaHttpClient *McmaHttpClient, url string, body *bytes.Reader) (*http.Response, error)) (interface{}, error) {
+func (resourceEndpointClient *ResourceEndpointClient) execute(ctx context.Context, t reflect.Type, url string, body interface{}, execute func(ctx context.Context, mcmaHttpClient *McmaHttpClient, url string, body *bytes.Reader) (*http.Response, error)) (interface{}, error) {
 	mcmaHttpClient, err := resourceEndpointClient.getMcmaHttpClient()
 	if err != nil {
 		return nil, err
@@ -89,7 +102,7 @@ func (resourceEndpointClient *ResourceEndpointClient) execute(t reflect.Type, ur
 	if err != nil {
 		return nil, err
 	}
-	resp, err := execute(mcmaHttpClient, url, reqBody)
+	resp, err := execute(ctx, mcmaHttpClient, url, reqBody)
 	if err != nil {
 		return nil, err
 	}
@@ -99,52 +112,33 @@ func (resourceEndpointClient *ResourceEndpointClient) execute(t reflect.Type, ur
 	return readJsonRespBody(resp, t)
 }
 
-func (resourceEndpointClient *ResourceEndpointClient) Query(t reflect.Type, url string, queryParameters QueryParameters) (model.QueryResults, error) {
-	return resourceEndpointClient.QueryWithRetries(t, url, queryParameters, DefaultRetryOptions)
+func (resourceEndpointClient *ResourceEndpointClient) Query(t reflect.Type, url string, queryParameters *QueryParameters) (model.QueryResults, error) {
+	return resourceEndpointClient.QueryWithRetriesContext(context.Background(), t, url, queryParameters, resourceEndpointClient.getRetryOptions())
 }
-func (resourceEndpointClient *ResourceEndpointClient) QueryWithRetries(t reflect.Type, url string, queryParameters QueryParameters, retryOpts RetryOptions) (model.QueryResults, error) {
-	var queryResults model.QueryResults
-	mcmaHttpClient, err := resourceEndpointClient.getMcmaHttpClient()
+func (resourceEndpointClient *ResourceEndpointClient) QueryWithRetries(t reflect.Type, url string, queryParameters *QueryParameters, retryOpts RetryOptions) (model.QueryResults, error) {
+	return resourceEndpointClient.QueryWithRetriesContext(context.Background(), t, url, queryParameters, retryOpts)
+}
+func (resourceEndpointClient *ResourceEndpointClient) QueryContext(ctx context.Context, t reflect.Type, url string, queryParameters *QueryParameters) (model.QueryResults, error) {
+	return resourceEndpointClient.QueryWithRetriesContext(ctx, t, url, queryParameters, resourceEndpointClient.getRetryOptions())
+}
+func (resourceEndpointClient *ResourceEndpointClient) QueryWithRetriesContext(ctx context.Context, t reflect.Type, url string, queryParameters *QueryParameters, retryOpts RetryOptions) (model.QueryResults, error) {
+	queryResults, err := resourceEndpointClient.QueryMapsWithRetriesContext(ctx, url, queryParameters, retryOpts)
 	if err != nil {
 		return queryResults, err
 	}
-
-	if url, err = resourceEndpointClient.getFullUrl(url); err != nil {
-		return queryResults, err
-	}
-	if len(queryParameters) > 0 {
-		url += "?"
-		for _, p := range queryParameters {
-			url += fmt.Sprintf("%s=%s", p.key, p.value)
-		}
-	}
-
-	getResp, err := mcmaHttpClient.GetWithRetries(url, true, retryOpts)
-	if err != nil {
-		return queryResults, fmt.Errorf("failed to query %v: %v", url, err)
-	}
-
-	body, err := readJsonRespBody(getResp, reflect.TypeOf(queryResults))
-	if err != nil {
-		return queryResults, fmt.Errorf("failed to get query results for %v: %v", url, err)
-	}
-
-	queryResults = body.(model.QueryResults)
-
-	results, err := queryResults.GetResults(t)
-	if err != nil {
-		return queryResults, fmt.Errorf("failed to get typed query results for %v: %v", url, err)
-	}
-
-	queryResults.Results = results
-
-	return queryResults, err
+	return getTypedQueryResults(queryResults, t, url)
 }
 
-func (resourceEndpointClient *ResourceEndpointClient) QueryMaps(url string, queryParameters QueryParameters) (model.QueryResults, error) {
-	return resourceEndpointClient.QueryMapsWithRetries(url, queryParameters, DefaultRetryOptions)
+func (resourceEndpointClient *ResourceEndpointClient) QueryMaps(url string, queryParameters *QueryParameters) (model.QueryResults, error) {
+	return resourceEndpointClient.QueryMapsWithRetriesContext(context.Background(), url, queryParameters, resourceEndpointClient.getRetryOptions())
+}
+func (resourceEndpointClient *ResourceEndpointClient) QueryMapsWithRetries(url string, queryParameters *QueryParameters, retryOpts RetryOptions) (model.QueryResults, error) {
+	return resourceEndpointClient.QueryMapsWithRetriesContext(context.Background(), url, queryParameters, retryOpts)
 }
-func (resourceEndpointClient *ResourceEndpointClient) QueryMapsWithRetries(url string, queryParameters QueryParameters, retryOpts RetryOptions) (model.QueryResults, error) {
+func (resourceEndpointClient *ResourceEndpointClient) QueryMapsContext(ctx context.Context, url string, queryParameters *QueryParameters) (model.QueryResults, error) {
+	return resourceEndpointClient.QueryMapsWithRetriesContext(ctx, url, queryParameters, resourceEndpointClient.getRetryOptions())
+}
+func (resourceEndpointClient *ResourceEndpointClient) QueryMapsWithRetriesContext(ctx context.Context, url string, queryParameters *QueryParameters, retryOpts RetryOptions) (model.QueryResults, error) {
 	var queryResults model.QueryResults
 	mcmaHttpClient, err := resourceEndpointClient.getMcmaHttpClient()
 	if err != nil {
@@ -154,41 +148,36 @@ func (resourceEndpointClient *ResourceEndpointClient) QueryMapsWithRetries(url s
 	if url, err = resourceEndpointClient.getFullUrl(url); err != nil {
 		return queryResults, err
 	}
-	if len(queryParameters) > 0 {
-		url += "?"
-		for _, p := range queryParameters {
-			url += fmt.Sprintf("%s=%s", p.key, p.value)
-		}
-	}
-
-	getResp, err := mcmaHttpClient.GetWithRetries(url, true, retryOpts)
-	if err != nil {
-		return queryResults, fmt.Errorf("failed to query %v: %v", url, err)
-	}
-
-	body, err := readJsonRespBody(getResp, reflect.TypeOf(queryResults))
-	if err != nil {
-		return queryResults, fmt.Errorf("failed to get query results for %v: %v", url, err)
-	}
-
-	return body.(model.QueryResults), err
+	return mcmaHttpClient.queryMapsWithRetriesContext(ctx, url, queryParameters, retryOpts)
 }
 
 func (resourceEndpointClient *ResourceEndpointClient) Get(t reflect.Type, url string) (interface{}, error) {
-	return resourceEndpointClient.GetWithRetries(t, url, DefaultRetryOptions)
+	return resourceEndpointClient.GetWithRetriesContext(context.Background(), t, url, resourceEndpointClient.getRetryOptions())
 }
 func (resourceEndpointClient *ResourceEndpointClient) GetWithRetries(t reflect.Type, url string, retryOpts RetryOptions) (interface{}, error) {
-	return resourceEndpointClient.execute(t, url, nil, func(client *McmaHttpClient, url string, body *bytes.Reader) (*http.Response, error) {
-		return client.GetWithRetries(url, false, retryOpts)
+	return resourceEndpointClient.GetWithRetriesContext(context.Background(), t, url, retryOpts)
+}
+func (resourceEndpointClient *ResourceEndpointClient) GetContext(ctx context.Context, t reflect.Type, url string) (interface{}, error) {
+	return resourceEndpointClient.GetWithRetriesContext(ctx, t, url, resourceEndpointClient.getRetryOptions())
+}
+func (resourceEndpointClient *ResourceEndpointClient) GetWithRetriesContext(ctx context.Context, t reflect.Type, url string, retryOpts RetryOptions) (interface{}, error) {
+	return resourceEndpointClient.execute(ctx, t, url, nil, func(ctx context.Context, client *McmaHttpClient, url string, body *bytes.Reader) (*http.Response, error) {
+		return client.GetWithRetriesContext(ctx, url, false, retryOpts)
 	})
 }
 
 func (resourceEndpointClient *ResourceEndpointClient) GetResource(url string) (map[string]interface{}, error) {
-	return resourceEndpointClient.GetResourceWithRetries(url, DefaultRetryOptions)
+	return resourceEndpointClient.GetResourceWithRetriesContext(context.Background(), url, resourceEndpointClient.getRetryOptions())
 }
 func (resourceEndpointClient *ResourceEndpointClient) GetResourceWithRetries(url string, retryOpts RetryOptions) (map[string]interface{}, error) {
+	return resourceEndpointClient.GetResourceWithRetriesContext(context.Background(), url, retryOpts)
+}
+func (resourceEndpointClient *ResourceEndpointClient) GetResourceContext(ctx context.Context, url string) (map[string]interface{}, error) {
+	return resourceEndpointClient.GetResourceWithRetriesContext(ctx, url, resourceEndpointClient.getRetryOptions())
+}
+func (resourceEndpointClient *ResourceEndpointClient) GetResourceWithRetriesContext(ctx context.Context, url string, retryOpts RetryOptions) (map[string]interface{}, error) {
 	var m map[string]interface{}
-	mi, err := resourceEndpointClient.GetWithRetries(reflect.TypeOf(m), url, retryOpts)
+	mi, err := resourceEndpointClient.GetWithRetriesContext(ctx, reflect.TypeOf(m), url, retryOpts)
 	if err != nil {
 		return nil, err
 	}
@@ -199,43 +188,73 @@ func (resourceEndpointClient *ResourceEndpointClient) GetResourceWithRetries(url
 }
 
 func (resourceEndpointClient *ResourceEndpointClient) Post(t reflect.Type, url string, body interface{}) (interface{}, error) {
-	return resourceEndpointClient.PostWithRetries(t, url, body, DefaultRetryOptions)
+	return resourceEndpointClient.PostWithRetriesContext(context.Background(), t, url, body, resourceEndpointClient.getRetryOptions())
 }
 func (resourceEndpointClient *ResourceEndpointClient) PostWithRetries(t reflect.Type, url string, body interface{}, retryOpts RetryOptions) (interface{}, error) {
-	return resourceEndpointClient.execute(t, url, body, func(client *McmaHttpClient, url string, body *bytes.Reader) (*http.Response, error) {
-		return client.PostWithRetries(url, body, retryOpts)
+	return resourceEndpointClient.PostWithRetriesContext(context.Background(), t, url, body, retryOpts)
+}
+func (resourceEndpointClient *ResourceEndpointClient) PostContext(ctx context.Context, t reflect.Type, url string, body interface{}) (interface{}, error) {
+	return resourceEndpointClient.PostWithRetriesContext(ctx, t, url, body, resourceEndpointClient.getRetryOptions())
+}
+func (resourceEndpointClient *ResourceEndpointClient) PostWithRetriesContext(ctx context.Context, t reflect.Type, url string, body interface{}, retryOpts RetryOptions) (interface{}, error) {
+	return resourceEndpointClient.execute(ctx, t, url, body, func(ctx context.Context, client *McmaHttpClient, url string, body *bytes.Reader) (*http.Response, error) {
+		return client.PostWithRetriesContext(ctx, url, body, retryOpts)
 	})
 }
 
 func (resourceEndpointClient *ResourceEndpointClient) PostResource(url string, body map[string]interface{}) (interface{}, error) {
-	return resourceEndpointClient.PostResourceWithRetries(url, body, DefaultRetryOptions)
+	return resourceEndpointClient.PostResourceWithRetriesContext(context.Background(), url, body, resourceEndpointClient.getRetryOptions())
 }
 func (resourceEndpointClient *ResourceEndpointClient) PostResourceWithRetries(url string, body map[string]interface{}, retryOpts RetryOptions) (interface{}, error) {
-	return resourceEndpointClient.PostWithRetries(reflect.TypeOf(body), url, body, retryOpts)
+	return resourceEndpointClient.PostResourceWithRetriesContext(context.Background(), url, body, retryOpts)
+}
+func (resourceEndpointClient *ResourceEndpointClient) PostResourceContext(ctx context.Context, url string, body map[string]interface{}) (interface{}, error) {
+	return resourceEndpointClient.PostResourceWithRetriesContext(ctx, url, body, resourceEndpointClient.getRetryOptions())
+}
+func (resourceEndpointClient *ResourceEndpointClient) PostResourceWithRetriesContext(ctx context.Context, url string, body map[string]interface{}, retryOpts RetryOptions) (interface{}, error) {
+	return resourceEndpointClient.PostWithRetriesContext(ctx, reflect.TypeOf(body), url, body, retryOpts)
 }
 
 func (resourceEndpointClient *ResourceEndpointClient) Put(t reflect.Type, url string, body interface{}) (interface{}, error) {
-	return resourceEndpointClient.PutWithRetries(t, url, body, DefaultRetryOptions)
+	return resourceEndpointClient.PutWithRetriesContext(context.Background(), t, url, body, resourceEndpointClient.getRetryOptions())
 }
 func (resourceEndpointClient *ResourceEndpointClient) PutWithRetries(t reflect.Type, url string, body interface{}, retryOpts RetryOptions) (interface{}, error) {
-	return resourceEndpointClient.execute(t, url, body, func(client *McmaHttpClient, url string, body *bytes.Reader) (*http.Response, error) {
-		return client.PutWithRetries(url, body, retryOpts)
+	return resourceEndpointClient.PutWithRetriesContext(context.Background(), t, url, body, retryOpts)
+}
+func (resourceEndpointClient *ResourceEndpointClient) PutContext(ctx context.Context, t reflect.Type, url string, body interface{}) (interface{}, error) {
+	return resourceEndpointClient.PutWithRetriesContext(ctx, t, url, body, resourceEndpointClient.getRetryOptions())
+}
+func (resourceEndpointClient *ResourceEndpointClient) PutWithRetriesContext(ctx context.Context, t reflect.Type, url string, body interface{}, retryOpts RetryOptions) (interface{}, error) {
+	return resourceEndpointClient.execute(ctx, t, url, body, func(ctx context.Context, client *McmaHttpClient, url string, body *bytes.Reader) (*http.Response, error) {
+		return client.PutWithRetriesContext(ctx, url, body, retryOpts)
 	})
 }
 
 func (resourceEndpointClient *ResourceEndpointClient) PutResource(url string, body map[string]interface{}) (interface{}, error) {
-	return resourceEndpointClient.PutResourceWithRetries(url, body, DefaultRetryOptions)
+	return resourceEndpointClient.PutResourceWithRetriesContext(context.Background(), url, body, resourceEndpointClient.getRetryOptions())
 }
 func (resourceEndpointClient *ResourceEndpointClient) PutResourceWithRetries(url string, body map[string]interface{}, retryOpts RetryOptions) (interface{}, error) {
-	return resourceEndpointClient.PutWithRetries(reflect.TypeOf(body), url, body, retryOpts)
+	return resourceEndpointClient.PutResourceWithRetriesContext(context.Background(), url, body, retryOpts)
+}
+func (resourceEndpointClient *ResourceEndpointClient) PutResourceContext(ctx context.Context, url string, body map[string]interface{}) (interface{}, error) {
+	return resourceEndpointClient.PutResourceWithRetriesContext(ctx, url, body, resourceEndpointClient.getRetryOptions())
+}
+func (resourceEndpointClient *ResourceEndpointClient) PutResourceWithRetriesContext(ctx context.Context, url string, body map[string]interface{}, retryOpts RetryOptions) (interface{}, error) {
+	return resourceEndpointClient.PutWithRetriesContext(ctx, reflect.TypeOf(body), url, body, retryOpts)
 }
 
 func (resourceEndpointClient *ResourceEndpointClient) Delete(url string) error {
-	return resourceEndpointClient.DeleteWithRetries(url, DefaultRetryOptions)
+	return resourceEndpointClient.DeleteWithRetriesContext(context.Background(), url, resourceEndpointClient.getRetryOptions())
 }
 func (resourceEndpointClient *ResourceEndpointClient) DeleteWithRetries(url string, retryOpts RetryOptions) error {
-	_, err := resourceEndpointClient.execute(nil, url, nil, func(client *McmaHttpClient, url string, body *bytes.Reader) (*http.Response, error) {
-		return client.DeleteWithRetries(url, retryOpts)
+	return resourceEndpointClient.DeleteWithRetriesContext(context.Background(), url, retryOpts)
+}
+func (resourceEndpointClient *ResourceEndpointClient) DeleteContext(ctx context.Context, url string) error {
+	return resourceEndpointClient.DeleteWithRetriesContext(ctx, url, resourceEndpointClient.getRetryOptions())
+}
+func (resourceEndpointClient *ResourceEndpointClient) DeleteWithRetriesContext(ctx context.Context, url string, retryOpts RetryOptions) error {
+	_, err := resourceEndpointClient.execute(ctx, nil, url, nil, func(ctx context.Context, client *McmaHttpClient, url string, body *bytes.Reader) (*http.Response, error) {
+		return client.DeleteWithRetriesContext(ctx, url, retryOpts)
 	})
 	return err
 }
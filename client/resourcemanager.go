@@ -2,44 +2,73 @@ package mcmaclient
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"net/http"
 	"reflect"
 	"strings"
 	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/ebu/mcma-libraries-go/model"
 )
 
 type ResourceManager struct {
-	authProvider            *AuthProvider
-	httpClient              *http.Client
-	mcmaHttpClient          *McmaHttpClient
-	serviceRegistryUrl      string
-	serviceRegistryAuthType string
-	tracker                 *model.McmaTracker
-	services                []*ServiceClient
-	initMutex               sync.Mutex
+	authProvider              *AuthProvider
+	httpClient                *http.Client
+	mcmaHttpClient            *McmaHttpClient
+	serviceRegistryUrl        string
+	serviceRegistryAuthType   string
+	tracker                   *model.McmaTracker
+	services                  []*ServiceClient
+	servicesLoadedAt          time.Time
+	servicesMutex             sync.RWMutex
+	registryCacheTtl          time.Duration
+	refreshing                atomic.Bool
+	lastRefreshErr            error
+	backgroundRefreshCancel   context.CancelFunc
+	backgroundRefreshInterval time.Duration
+	snapshotStore             ServiceSnapshotStore
+	staticServices            []model.Service
+	retryOptions              *RetryOptions
+	userAgent                 string
+	middlewares               []Middleware
+	logger                    Logger
+	initMutex                 sync.Mutex
+	queryConcurrency          int
 }
 
 func (resourceManager *ResourceManager) getMcmaHttpClient() *McmaHttpClient {
+	resourceManager.servicesMutex.Lock()
+	defer resourceManager.servicesMutex.Unlock()
 	if resourceManager.mcmaHttpClient == nil {
 		resourceManager.mcmaHttpClient = &McmaHttpClient{
 			httpClient:    resourceManager.httpClient,
 			authenticator: resourceManager.authProvider.GetDefault(),
+			retryOptions:  resourceManager.retryOptions,
+			userAgent:     resourceManager.userAgent,
+			middlewares:   resourceManager.middlewares,
 		}
 	}
 	return resourceManager.mcmaHttpClient
 }
 
-func (resourceManager *ResourceManager) getResourceEndpoint(url string) (*ResourceEndpointClient, error) {
+func (resourceManager *ResourceManager) getRetryOptions() RetryOptions {
+	if resourceManager.retryOptions != nil {
+		return *resourceManager.retryOptions
+	}
+	return DefaultRetryOptions
+}
+
+func (resourceManager *ResourceManager) getResourceEndpoint(ctx context.Context, url string) (*ResourceEndpointClient, error) {
 	if url == "" {
 		return nil, nil
 	}
-	if err := resourceManager.EnsureInit(); err != nil {
+	if err := resourceManager.EnsureInitContext(ctx); err != nil {
 		return nil, err
 	}
-	for _, s := range resourceManager.services {
+	for _, s := range resourceManager.getServices() {
 		s.loadResources()
 		for _, r := range s.resources {
 			if r.hasMatchingHttpEndpoint(url) {
@@ -51,69 +80,122 @@ func (resourceManager *ResourceManager) getResourceEndpoint(url string) (*Resour
 }
 
 func (resourceManager *ResourceManager) Init() error {
-	serviceRegistryUrl := resourceManager.serviceRegistryUrl
-	if serviceRegistryUrl[len(serviceRegistryUrl)-1] == '/' {
-		serviceRegistryUrl = serviceRegistryUrl[:len(serviceRegistryUrl)-1]
+	return resourceManager.InitContext(context.Background())
+}
+
+func (resourceManager *ResourceManager) InitContext(ctx context.Context) error {
+	services, err := resourceManager.queryServices(ctx)
+	if err != nil {
+		return err
 	}
+	resourceManager.setServices(services, time.Now())
+	resourceManager.saveSnapshot(ctx, services)
+	return nil
+}
 
-	serviceRegistryClient := &ServiceClient{
-		authProvider: resourceManager.authProvider,
-		httpClient:   resourceManager.httpClient,
-		service: model.Service{
-			Name:     "Service Registry",
-			AuthType: resourceManager.serviceRegistryAuthType,
-			Resources: []model.ResourceEndpoint{
-				{
-					ResourceType: "Service",
-					HttpEndpoint: serviceRegistryUrl + "/services",
-				},
-				{
-					ResourceType: "JobProfile",
-					HttpEndpoint: serviceRegistryUrl + "/job-profiles",
-				},
+func (resourceManager *ResourceManager) getServiceRegistryClient() *ServiceClient {
+	resourceManager.servicesMutex.RLock()
+	defer resourceManager.servicesMutex.RUnlock()
+	return resourceManager.newServiceRegistryClient()
+}
+
+// newServiceRegistryClient must be called with the services mutex held
+func (resourceManager *ResourceManager) newServiceRegistryClient() *ServiceClient {
+	serviceRegistryUrl := strings.TrimSuffix(resourceManager.serviceRegistryUrl, "/")
+
+	return resourceManager.newServiceClient(model.Service{
+		Name:     "Service Registry",
+		AuthType: resourceManager.serviceRegistryAuthType,
+		Resources: []model.ResourceEndpoint{
+			{
+				ResourceType: "Service",
+				HttpEndpoint: serviceRegistryUrl + "/services",
+			},
+			{
+				ResourceType: "JobProfile",
+				HttpEndpoint: serviceRegistryUrl + "/job-profiles",
 			},
 		},
-		tracker: resourceManager.tracker,
+	})
+}
+
+// newServiceClient must be called with the services mutex held
+func (resourceManager *ResourceManager) newServiceClient(service model.Service) *ServiceClient {
+	return &ServiceClient{
+		authProvider: resourceManager.authProvider,
+		httpClient:   resourceManager.httpClient,
+		service:      service,
+		tracker:      resourceManager.tracker,
+		retryOptions: resourceManager.retryOptions,
+		userAgent:    resourceManager.userAgent,
+		middlewares:  resourceManager.middlewares,
 	}
+}
 
-	resourceManager.services = append(resourceManager.services[:0], serviceRegistryClient)
+func (resourceManager *ResourceManager) queryServices(ctx context.Context) ([]model.Service, error) {
+	if staticServices := resourceManager.getStaticServices(); staticServices != nil {
+		return staticServices, nil
+	}
+
+	serviceRegistryClient := resourceManager.getServiceRegistryClient()
 
 	servicesEndpoint, found := serviceRegistryClient.GetResourceEndpointClientByType(reflect.TypeOf(model.Service{}))
 	if !found {
-		return fmt.Errorf("service resource endpoint not found")
+		return nil, fmt.Errorf("service resource endpoint not found")
 	}
 
-	serviceQueryResults, err := servicesEndpoint.QueryWithRetries(reflect.TypeOf(model.Service{}), "", nil, RetryOptions{
-		ShouldRetry: func(resp *http.Response, err error) bool {
-			return DefaultShouldRetry(resp, err) || resp.StatusCode == 404
-		},
-		Intervals: DefaultRetryIntervals,
-	})
+	// the service registry can briefly return 404 while it is being deployed, so that is retried as well
+	retryOptions := resourceManager.getRetryOptions()
+	shouldRetry := retryOptions.getShouldRetry()
+	retryOptions.ShouldRetry = func(resp *http.Response, err error) bool {
+		return shouldRetry(resp, err) || (resp != nil && resp.StatusCode == 404)
+	}
+	results, err := servicesEndpoint.QueryAllWithRetries(ctx, reflect.TypeOf(model.Service{}), "", nil, 0, retryOptions)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	for _, r := range serviceQueryResults.Results {
+	var services []model.Service
+	for _, r := range results {
 		service := r.(model.Service)
 		if service.Name != serviceRegistryClient.service.Name {
-			serviceClient := &ServiceClient{
-				authProvider: resourceManager.authProvider,
-				httpClient:   resourceManager.httpClient,
-				service:      service,
-				tracker:      resourceManager.tracker,
-			}
-			resourceManager.services = append(resourceManager.services, serviceClient)
+			services = append(services, service)
 		}
 	}
+	return services, nil
+}
 
-	return nil
+func (resourceManager *ResourceManager) newServiceClients(services []model.Service) []*ServiceClient {
+	resourceManager.servicesMutex.RLock()
+	defer resourceManager.servicesMutex.RUnlock()
+
+	var serviceClients []*ServiceClient
+	if resourceManager.serviceRegistryUrl != "" {
+		serviceClients = append(serviceClients, resourceManager.newServiceRegistryClient())
+	}
+	for _, service := range services {
+		serviceClients = append(serviceClients, resourceManager.newServiceClient(service))
+	}
+	return serviceClients
 }
 
 func (resourceManager *ResourceManager) EnsureInit() error {
+	return resourceManager.EnsureInitContext(context.Background())
+}
+
+func (resourceManager *ResourceManager) EnsureInitContext(ctx context.Context) error {
+	services, loadedAt := resourceManager.getServicesAndLoadedAt()
+	if len(services) > 0 {
+		if resourceManager.isStale(loadedAt) {
+			resourceManager.refreshInBackground()
+		}
+		return nil
+	}
+
 	resourceManager.initMutex.Lock()
 	defer resourceManager.initMutex.Unlock()
-	if len(resourceManager.services) == 0 {
-		err := resourceManager.Init()
+	if len(resourceManager.getServices()) == 0 && !resourceManager.initFromSnapshot(ctx) {
+		err := resourceManager.InitContext(ctx)
 		if err != nil {
 			return err
 		}
@@ -121,88 +203,62 @@ func (resourceManager *ResourceManager) EnsureInit() error {
 	return nil
 }
 
-func (resourceManager *ResourceManager) Query(t reflect.Type, filter []struct {
-	key   string
-	value string
-}) ([]interface{}, error) {
-	if err := resourceManager.EnsureInit(); err != nil {
+// Query returns the resources of type t from every resource endpoint that serves them. If any endpoint fails no
+// results are returned and the error is a *QueryError. Use the generic Query to also get the results from the
+// endpoints that succeeded.
+func (resourceManager *ResourceManager) Query(t reflect.Type, filter *QueryParameters) ([]interface{}, error) {
+	return resourceManager.QueryContext(context.Background(), t, filter)
+}
+
+func (resourceManager *ResourceManager) QueryContext(ctx context.Context, t reflect.Type, filter *QueryParameters) ([]interface{}, error) {
+	results, err := resourceManager.queryByType(ctx, t, filter)
+	if err != nil {
 		return nil, err
 	}
-	anyMatchingClients := false
-	usedHttpEndpoints := make(map[string]struct{})
-	var results []interface{}
-	var errs []string
-	for _, s := range resourceManager.services {
-		if resourceEndpointClient, matched := s.GetResourceEndpointClientByType(t); matched {
-			if _, alreadyUsed := usedHttpEndpoints[resourceEndpointClient.getHttpEndpoint()]; alreadyUsed {
-				continue
-			}
-			anyMatchingClients = true
-			if queryResults, err := resourceEndpointClient.Query(t, "", filter); err == nil {
-				for _, r := range queryResults.Results {
-					results = append(results, r)
-				}
-				usedHttpEndpoints[resourceEndpointClient.getHttpEndpoint()] = struct{}{}
-			} else {
-				errs = append(errs, err.Error())
-			}
-		}
-	}
-	if !anyMatchingClients {
-		return nil, fmt.Errorf("no available resource endpoints for resource of type '%s'", t.String())
-	}
-	if len(usedHttpEndpoints) == 0 {
-		return nil, fmt.Errorf(strings.Join(errs, "\n"))
-	}
 	return results, nil
 }
 
-func (resourceManager *ResourceManager) QueryResources(resourceType string, filter []struct {
-	key   string
-	value string
-}) ([]interface{}, error) {
-	if err := resourceManager.EnsureInit(); err != nil {
+// queryByType returns the results from the endpoints that succeeded even if others failed
+func (resourceManager *ResourceManager) queryByType(ctx context.Context, t reflect.Type, filter *QueryParameters) ([]interface{}, error) {
+	return resourceManager.queryResourceEndpoints(ctx, t.String(), func(serviceClient *ServiceClient) (*ResourceEndpointClient, bool) {
+		return serviceClient.GetResourceEndpointClientByType(t)
+	}, func(ctx context.Context, resourceEndpointClient *ResourceEndpointClient) ([]interface{}, error) {
+		return resourceEndpointClient.QueryAll(ctx, t, "", filter, 0)
+	})
+}
+
+// QueryResources returns the resources with the given type name, as maps, from every resource endpoint that serves
+// them. If any endpoint fails no results are returned and the error is a *QueryError.
+func (resourceManager *ResourceManager) QueryResources(resourceType string, filter *QueryParameters) ([]interface{}, error) {
+	return resourceManager.QueryResourcesContext(context.Background(), resourceType, filter)
+}
+
+func (resourceManager *ResourceManager) QueryResourcesContext(ctx context.Context, resourceType string, filter *QueryParameters) ([]interface{}, error) {
+	results, err := resourceManager.queryResourceEndpoints(ctx, resourceType, func(serviceClient *ServiceClient) (*ResourceEndpointClient, bool) {
+		return serviceClient.GetResourceEndpointClientByTypeName(resourceType)
+	}, func(ctx context.Context, resourceEndpointClient *ResourceEndpointClient) ([]interface{}, error) {
+		return resourceEndpointClient.QueryAll(ctx, nil, "", filter, 0)
+	})
+	if err != nil {
 		return nil, err
 	}
-	anyMatchingClients := false
-	usedHttpEndpoints := make(map[string]struct{})
-	var results []interface{}
-	var errs []string
-	for _, s := range resourceManager.services {
-		if resourceEndpointClient, matched := s.GetResourceEndpointClientByTypeName(resourceType); matched {
-			if _, alreadyUsed := usedHttpEndpoints[resourceEndpointClient.getHttpEndpoint()]; alreadyUsed {
-				continue
-			}
-			anyMatchingClients = true
-			if queryResults, err := resourceEndpointClient.QueryMaps("", filter); err == nil {
-				for _, r := range queryResults.Results {
-					results = append(results, r)
-				}
-				usedHttpEndpoints[resourceEndpointClient.getHttpEndpoint()] = struct{}{}
-			} else {
-				errs = append(errs, err.Error())
-			}
-		}
-	}
-	if !anyMatchingClients {
-		return nil, fmt.Errorf("no available resource endpoints for resource of type '%s'", resourceType)
-	}
-	if len(usedHttpEndpoints) == 0 {
-		return nil, fmt.Errorf(strings.Join(errs, "\n"))
-	}
 	return results, nil
 }
 
 func (resourceManager *ResourceManager) GetResource(resourceType string, resourceId string) (map[string]interface{}, error) {
-	if err := resourceManager.EnsureInit(); err != nil {
+	return resourceManager.GetResourceContext(context.Background(), resourceType, resourceId)
+}
+
+func (resourceManager *ResourceManager) GetResourceContext(ctx context.Context, resourceType string, resourceId string) (map[string]interface{}, error) {
+	if err := resourceManager.EnsureInitContext(ctx); err != nil {
 		return nil, err
 	}
-	for _, s := range resourceManager.services {
+	for _, s := range resourceManager.getServices() {
 		if resourceEndpointClient, matched := s.GetResourceEndpointClientByTypeNameAndUrl(resourceType, resourceId); matched {
-			return resourceEndpointClient.GetResource(resourceId)
+			return resourceEndpointClient.GetResourceContext(ctx, resourceId)
 		}
 	}
-	resp, err := resourceManager.getMcmaHttpClient().Get(resourceId, false)
+	resp, err := resourceManager.getMcmaHttpClient().GetContext(ctx, resourceId, false)
 	if err != nil {
 		return nil, err
 	}
@@ -220,17 +276,21 @@ func (resourceManager *ResourceManager) GetResource(resourceType string, resourc
 }
 
 func (resourceManager *ResourceManager) Get(t reflect.Type, resourceId string) (interface{}, error) {
-	if err := resourceManager.EnsureInit(); err != nil {
+	return resourceManager.GetContext(context.Background(), t, resourceId)
+}
+
+func (resourceManager *ResourceManager) GetContext(ctx context.Context, t reflect.Type, resourceId string) (interface{}, error) {
+	if err := resourceManager.EnsureInitContext(ctx); err != nil {
 		return nil, err
 	}
 	if t.Kind() != reflect.Map {
-		for _, s := range resourceManager.services {
+		for _, s := range resourceManager.getServices() {
 			if resourceEndpointClient, matched := s.GetResourceEndpointClientByTypeAndUrl(t, resourceId); matched {
-				return resourceEndpointClient.Get(t, resourceId)
+				return resourceEndpointClient.GetContext(ctx, t, resourceId)
 			}
 		}
 	}
-	resp, err := resourceManager.getMcmaHttpClient().Get(resourceId, false)
+	resp, err := resourceManager.getMcmaHttpClient().GetContext(ctx, resourceId, false)
 	if err != nil {
 		return nil, err
 	}
@@ -247,16 +307,20 @@ func (resourceManager *ResourceManager) Get(t reflect.Type, resourceId string) (
 }
 
 func (resourceManager *ResourceManager) Create(resource interface{}) (interface{}, error) {
-	if err := resourceManager.EnsureInit(); err != nil {
+	return resourceManager.CreateContext(context.Background(), resource)
+}
+
+func (resourceManager *ResourceManager) CreateContext(ctx context.Context, resource interface{}) (interface{}, error) {
+	if err := resourceManager.EnsureInitContext(ctx); err != nil {
 		return nil, err
 	}
 
 	t := reflect.TypeOf(resource)
 	var id string
 	if t.Kind() != reflect.Map {
-		for _, s := range resourceManager.services {
+		for _, s := range resourceManager.getServices() {
 			if resourceEndpointClient, matched := s.GetResourceEndpointClientByType(t); matched {
-				return resourceEndpointClient.Post(t, "", resource)
+				return resourceEndpointClient.PostContext(ctx, t, "", resource)
 			}
 		}
 
@@ -272,9 +336,9 @@ func (resourceManager *ResourceManager) Create(resource interface{}) (interface{
 		if !foundType {
 			return nil, fmt.Errorf("@type property not found in map")
 		}
-		for _, s := range resourceManager.services {
+		for _, s := range resourceManager.getServices() {
 			if resourceEndpointClient, matched := s.GetResourceEndpointClientByTypeName(resourceType.(string)); matched {
-				return resourceEndpointClient.PostResource("", resourceMap)
+				return resourceEndpointClient.PostResourceContext(ctx, "", resourceMap)
 			}
 		}
 
@@ -291,7 +355,7 @@ func (resourceManager *ResourceManager) Create(resource interface{}) (interface{
 		return nil, err
 	}
 
-	resp, err := resourceManager.getMcmaHttpClient().Post(id, jsonBody)
+	resp, err := resourceManager.getMcmaHttpClient().PostContext(ctx, id, jsonBody)
 	if err != nil {
 		return nil, err
 	}
@@ -306,7 +370,11 @@ func (resourceManager *ResourceManager) Create(resource interface{}) (interface{
 }
 
 func (resourceManager *ResourceManager) Update(resource interface{}) (interface{}, error) {
-	if err := resourceManager.EnsureInit(); err != nil {
+	return resourceManager.UpdateContext(context.Background(), resource)
+}
+
+func (resourceManager *ResourceManager) UpdateContext(ctx context.Context, resource interface{}) (interface{}, error) {
+	if err := resourceManager.EnsureInitContext(ctx); err != nil {
 		return nil, err
 	}
 
@@ -319,9 +387,9 @@ func (resourceManager *ResourceManager) Update(resource interface{}) (interface{
 			return nil, fmt.Errorf("cannot update - no id on resource")
 		}
 		id = idField.String()
-		for _, s := range resourceManager.services {
+		for _, s := range resourceManager.getServices() {
 			if resourceEndpointClient, matched := s.GetResourceEndpointClientByTypeAndUrl(t, id); matched {
-				return resourceEndpointClient.Put(t, id, resource)
+				return resourceEndpointClient.PutContext(ctx, t, id, resource)
 			}
 		}
 	} else {
@@ -335,9 +403,9 @@ func (resourceManager *ResourceManager) Update(resource interface{}) (interface{
 			return nil, fmt.Errorf("no resource endpoint available for type '%s' and no id on resource", resourceType)
 		}
 		id = idVal.(string)
-		for _, s := range resourceManager.services {
+		for _, s := range resourceManager.getServices() {
 			if resourceEndpointClient, matched := s.GetResourceEndpointClientByTypeNameAndUrl(resourceType.(string), id); matched {
-				return resourceEndpointClient.PutResource("", resourceMap)
+				return resourceEndpointClient.PutResourceContext(ctx, "", resourceMap)
 			}
 		}
 	}
@@ -348,7 +416,7 @@ func (resourceManager *ResourceManager) Update(resource interface{}) (interface{
 		return nil, err
 	}
 
-	resp, err := resourceManager.getMcmaHttpClient().Put(id, jsonBody)
+	resp, err := resourceManager.getMcmaHttpClient().PutContext(ctx, id, jsonBody)
 	if err != nil {
 		return nil, err
 	}
@@ -362,34 +430,46 @@ func (resourceManager *ResourceManager) Update(resource interface{}) (interface{
 }
 
 func (resourceManager *ResourceManager) DeleteResource(resourceType string, resourceId string) error {
-	if err := resourceManager.EnsureInit(); err != nil {
+	return resourceManager.DeleteResourceContext(context.Background(), resourceType, resourceId)
+}
+
+func (resourceManager *ResourceManager) DeleteResourceContext(ctx context.Context, resourceType string, resourceId string) error {
+	if err := resourceManager.EnsureInitContext(ctx); err != nil {
 		return err
 	}
-	for _, s := range resourceManager.services {
+	for _, s := range resourceManager.getServices() {
 		if resourceEndpointClient, matched := s.GetResourceEndpointClientByTypeNameAndUrl(resourceType, resourceId); matched {
-			err := resourceEndpointClient.Delete(resourceId)
+			err := resourceEndpointClient.DeleteContext(ctx, resourceId)
 			return err
 		}
 	}
-	_, err := resourceManager.getMcmaHttpClient().Delete(resourceId)
+	_, err := resourceManager.getMcmaHttpClient().DeleteContext(ctx, resourceId)
 	return err
 }
 
 func (resourceManager *ResourceManager) Delete(t reflect.Type, resourceId string) error {
-	if err := resourceManager.EnsureInit(); err != nil {
+	return resourceManager.DeleteContext(context.Background(), t, resourceId)
+}
+
+func (resourceManager *ResourceManager) DeleteContext(ctx context.Context, t reflect.Type, resourceId string) error {
+	if err := resourceManager.EnsureInitContext(ctx); err != nil {
 		return err
 	}
-	for _, s := range resourceManager.services {
+	for _, s := range resourceManager.getServices() {
 		if resourceEndpointClient, matched := s.GetResourceEndpointClientByTypeAndUrl(t, resourceId); matched {
-			err := resourceEndpointClient.Delete(resourceId)
+			err := resourceEndpointClient.DeleteContext(ctx, resourceId)
 			return err
 		}
 	}
-	_, err := resourceManager.getMcmaHttpClient().Delete(resourceId)
+	_, err := resourceManager.getMcmaHttpClient().DeleteContext(ctx, resourceId)
 	return err
 }
 
 func (resourceManager *ResourceManager) SendNotification(resourceId string, resource interface{}, notificationEndpoint model.NotificationEndpoint) error {
+	return resourceManager.SendNotificationContext(context.Background(), resourceId, resource, notificationEndpoint)
+}
+
+func (resourceManager *ResourceManager) SendNotificationContext(ctx context.Context, resourceId string, resource interface{}, notificationEndpoint model.NotificationEndpoint) error {
 	if notificationEndpoint.HttpEndpoint == "" {
 		return nil
 	}
@@ -401,62 +481,66 @@ func (resourceManager *ResourceManager) SendNotification(resourceId string, reso
 
 	var resourceEndpoint *ResourceEndpointClient
 	var err error
-	if resourceEndpoint, err = resourceManager.getResourceEndpoint(notificationEndpoint.HttpEndpoint); err != nil {
+	if resourceEndpoint, err = resourceManager.getResourceEndpoint(ctx, notificationEndpoint.HttpEndpoint); err != nil {
 		return err
 	}
 	if resourceEndpoint != nil {
-		_, err = resourceEndpoint.Post(nil, notificationEndpoint.HttpEndpoint, notification)
+		_, err = resourceEndpoint.PostContext(ctx, nil, notificationEndpoint.HttpEndpoint, notification)
 	} else {
 		jsonBody, err := getJsonReqBody(notification)
 		if err == nil {
-			_, err = resourceManager.getMcmaHttpClient().Post(notificationEndpoint.HttpEndpoint, jsonBody)
+			_, err = resourceManager.getMcmaHttpClient().PostContext(ctx, notificationEndpoint.HttpEndpoint, jsonBody)
 		}
 	}
 	return err
 }
 
+// SetHttpClient sets the http client used for every request. A nil client is ignored.
 func (resourceManager *ResourceManager) SetHttpClient(httpClient *http.Client) {
+	if httpClient == nil {
+		return
+	}
+	resourceManager.servicesMutex.Lock()
 	resourceManager.httpClient = httpClient
-	resourceManager.mcmaHttpClient = &McmaHttpClient{httpClient: httpClient}
-	resourceManager.services = resourceManager.services[:0]
+	resourceManager.mcmaHttpClient = nil
+	resourceManager.servicesMutex.Unlock()
+	resourceManager.Invalidate()
+}
+
+// Use adds middlewares that wrap every request made by the resource manager. They run in the order given, before the
+// built-in retry, tracker and auth middlewares.
+func (resourceManager *ResourceManager) Use(middlewares ...Middleware) {
+	resourceManager.servicesMutex.Lock()
+	resourceManager.middlewares = append(resourceManager.middlewares, middlewares...)
+	resourceManager.mcmaHttpClient = nil
+	resourceManager.servicesMutex.Unlock()
+	resourceManager.Invalidate()
 }
 
 func (resourceManager *ResourceManager) AddAuth(authType string, authenticator Authenticator) {
 	resourceManager.authProvider.Add(authType, authenticator)
 }
 
+// Deprecated: Use NewResourceManagerWithOptions, which returns a pointer. A ResourceManager holds mutexes, so the
+// returned value must not be copied once it has been used. Take its address and share the pointer instead.
 func NewResourceManager(serviceRegistryUrl string, serviceRegistryAuthType string) ResourceManager {
-	return ResourceManager{
-		authProvider:            newAuthProvider(),
-		httpClient:              &http.Client{},
-		serviceRegistryUrl:      serviceRegistryUrl,
-		serviceRegistryAuthType: serviceRegistryAuthType,
-	}
+	return *NewResourceManagerWithOptions(serviceRegistryUrl, WithServiceRegistryAuthType(serviceRegistryAuthType))
 }
 
+// Deprecated: Use NewResourceManagerWithOptions, which returns a pointer. A ResourceManager holds mutexes, so the
+// returned value must not be copied once it has been used. Take its address and share the pointer instead.
 func NewResourceManagerNoAuth(serviceRegistryUrl string) ResourceManager {
-	return ResourceManager{
-		authProvider:       newAuthProvider(),
-		httpClient:         &http.Client{},
-		serviceRegistryUrl: serviceRegistryUrl,
-	}
+	return *NewResourceManagerWithOptions(serviceRegistryUrl)
 }
 
+// Deprecated: Use NewResourceManagerWithOptions, which returns a pointer. A ResourceManager holds mutexes, so the
+// returned value must not be copied once it has been used. Take its address and share the pointer instead.
 func NewResourceManagerWithTracker(serviceRegistryUrl string, serviceRegistryAuthType string, tracker *model.McmaTracker) ResourceManager {
-	return ResourceManager{
-		authProvider:            newAuthProvider(),
-		httpClient:              &http.Client{},
-		serviceRegistryUrl:      serviceRegistryUrl,
-		serviceRegistryAuthType: serviceRegistryAuthType,
-		tracker:                 tracker,
-	}
+	return *NewResourceManagerWithOptions(serviceRegistryUrl, WithServiceRegistryAuthType(serviceRegistryAuthType), WithTracker(tracker))
 }
 
+// Deprecated: Use NewResourceManagerWithOptions, which returns a pointer. A ResourceManager holds mutexes, so the
+// returned value must not be copied once it has been used. Take its address and share the pointer instead.
 func NewResourceManagerWithTrackerNoAuth(serviceRegistryUrl string, tracker *model.McmaTracker) ResourceManager {
-	return ResourceManager{
-		authProvider:       newAuthProvider(),
-		httpClient:         &http.Client{},
-		serviceRegistryUrl: serviceRegistryUrl,
-		tracker:            tracker,
-	}
+	return *NewResourceManagerWithOptions(serviceRegistryUrl, WithTracker(tracker))
 }
@@ -1,13 +1,45 @@
 package mcmaclient
 
 import (
+	"context"
+	"fmt"
+	"io"
 	"net/http"
 	"time"
 )
 
+// maxDiscardBytes limits how much of a failed response is read before it is closed
+const maxDiscardBytes = 64 * 1024
+
 type RetryOptions struct {
 	ShouldRetry func(*http.Response, error) bool
-	Intervals   []time.Duration
+	// Intervals are the delays between retries when no Backoff is set
+	Intervals []time.Duration
+	Backoff   BackoffStrategy
+	// MaxAttempts caps the number of attempts, including the first one. Zero means no cap.
+	MaxAttempts int
+	// MaxElapsedTime stops retrying once the next retry would start after this much time has passed since the first
+	// attempt. Zero means no limit.
+	MaxElapsedTime time.Duration
+	// IgnoreRetryAfter disables waiting for the delay in the Retry-After header of 429 and 503 responses when it is
+	// longer than the back-off delay
+	IgnoreRetryAfter bool
+	// OnRetry is called before waiting for each retry with the response or error of the failed attempt
+	OnRetry func(attempt int, resp *http.Response, err error, delay time.Duration)
+}
+
+func (opts RetryOptions) getShouldRetry() func(*http.Response, error) bool {
+	if opts.ShouldRetry != nil {
+		return opts.ShouldRetry
+	}
+	return DefaultShouldRetry
+}
+
+func (opts RetryOptions) getBackoff() BackoffStrategy {
+	if opts.Backoff != nil {
+		return opts.Backoff
+	}
+	return FixedBackoff(opts.Intervals)
 }
 
 var DefaultShouldRetry = func(resp *http.Response, err error) bool {
@@ -36,19 +68,108 @@ func ExecuteWithDefaultRetries(client *http.Client, req *http.Request) (bool, *h
 }
 
 func ExecuteWithRetries(client *http.Client, req *http.Request, opts RetryOptions) (bool, *http.Response, error) {
-	res, err := client.Do(req)
-	if !opts.ShouldRetry(res, err) {
-		return true, res, err
-	}
+	return ExecuteWithRetriesContext(req.Context(), client, req, opts)
+}
 
-	for i := 0; i < len(opts.Intervals); i++ {
-		time.Sleep(opts.Intervals[i])
+func ExecuteWithDefaultRetriesContext(ctx context.Context, client *http.Client, req *http.Request) (bool, *http.Response, error) {
+	return ExecuteWithRetriesContext(ctx, client, req, DefaultRetryOptions)
+}
 
-		res, err = client.Do(req)
-		if !opts.ShouldRetry(res, err) {
+// ExecuteWithRetriesContext sends the request and retries it according to opts. Requests with a method that is not
+// idempotent, such as POST or PATCH, are only retried if they have an Idempotency-Key header. Requests with a body are
+// only retried if the body can be rebuilt with GetBody.
+func ExecuteWithRetriesContext(ctx context.Context, client *http.Client, req *http.Request, opts RetryOptions) (bool, *http.Response, error) {
+	return executeWithRetries(ctx, client.Do, req.WithContext(ctx), opts)
+}
+
+func executeWithRetries(ctx context.Context, do func(*http.Request) (*http.Response, error), req *http.Request, opts RetryOptions) (bool, *http.Response, error) {
+	start := time.Now()
+	shouldRetry := opts.getShouldRetry()
+	backoff := opts.getBackoff()
+	retryable := isRetryableRequest(req) && canRewindBody(req)
+
+	var delay time.Duration
+	for attempt := 1; ; attempt++ {
+		attemptReq := req
+		if attempt > 1 {
+			var err error
+			if attemptReq, err = rewindRequest(req); err != nil {
+				return false, nil, err
+			}
+		}
+
+		res, err := do(attemptReq)
+		if ctx.Err() != nil {
+			discardResponse(res)
+			return false, nil, ctx.Err()
+		}
+		if !retryable || !shouldRetry(res, err) {
 			return true, res, err
 		}
+		if opts.MaxAttempts > 0 && attempt >= opts.MaxAttempts {
+			return false, res, err
+		}
+
+		next, ok := backoff.NextDelay(attempt, delay)
+		if !ok {
+			return false, res, err
+		}
+		if !opts.IgnoreRetryAfter {
+			if retryAfter, ok := getRetryAfter(res); ok && retryAfter > next {
+				next = retryAfter
+			}
+		}
+		if opts.MaxElapsedTime > 0 && time.Since(start)+next > opts.MaxElapsedTime {
+			return false, res, err
+		}
+		delay = next
+
+		if opts.OnRetry != nil {
+			opts.OnRetry(attempt, res, err, delay)
+		}
+
+		// the failed response is not returned, so it is drained to let the connection be reused
+		discardResponse(res)
+
+		if err := sleepContext(ctx, delay); err != nil {
+			return false, nil, err
+		}
+	}
+}
+
+func canRewindBody(req *http.Request) bool {
+	return req.Body == nil || req.Body == http.NoBody || req.GetBody != nil
+}
+
+// rewindRequest copies the request with a fresh body, as the body of the previous attempt has been read
+func rewindRequest(req *http.Request) (*http.Request, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return req, nil
 	}
+	body, err := req.GetBody()
+	if err != nil {
+		return nil, fmt.Errorf("failed to rewind request body for retry: %v", err)
+	}
+	req = req.Clone(req.Context())
+	req.Body = body
+	return req, nil
+}
 
-	return false, res, err
+func discardResponse(res *http.Response) {
+	if res == nil || res.Body == nil {
+		return
+	}
+	_, _ = io.Copy(io.Discard, io.LimitReader(res.Body, maxDiscardBytes))
+	_ = res.Body.Close()
+}
+
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
 }
@@ -0,0 +1,312 @@
+package mcmaclient
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestRetryBackoffIsInterruptedByContext(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	client := &McmaHttpClient{httpClient: server.Client()}
+	ctx, cancel := context.WithTimeout(context.Background(), 300*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	_, err := client.GetContext(ctx, server.URL, true)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected context.DeadlineExceeded, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("expected retries to stop at the deadline, took %v", elapsed)
+	}
+}
+
+func TestInFlightRequestIsInterruptedByContext(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-release:
+		}
+	}))
+	defer server.Close()
+	defer close(release)
+
+	client := &McmaHttpClient{httpClient: server.Client()}
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(50*time.Millisecond, cancel)
+
+	_, err := client.GetContext(ctx, server.URL, true)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+}
+
+func TestEnsureInitContextIsCancellable(t *testing.T) {
+	server := newFakeMcmaServer()
+	defer server.Close()
+	server.handle("GET", "/services", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	})
+
+	resourceManager := NewResourceManagerWithOptions(server.URL)
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	if err := resourceManager.EnsureInitContext(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected context.DeadlineExceeded, got %v", err)
+	}
+}
+
+func TestRetryHonoursRetryAfter(t *testing.T) {
+	attempts := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		if attempts == 1 {
+			w.Header().Set("Retry-After", "1")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	var delays []time.Duration
+	client := &McmaHttpClient{httpClient: server.Client()}
+	_, err := client.GetWithRetries(server.URL, true, RetryOptions{
+		Intervals: []time.Duration{time.Millisecond},
+		OnRetry: func(attempt int, resp *http.Response, err error, delay time.Duration) {
+			delays = append(delays, delay)
+		},
+	})
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	if len(delays) != 1 || delays[0] != time.Second {
+		t.Errorf("expected a single retry after 1s, got %v", delays)
+	}
+}
+
+func TestRetryStopsAtMaxElapsedTime(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Retry-After", "60")
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	client := &McmaHttpClient{httpClient: server.Client()}
+	start := time.Now()
+	_, err := client.GetWithRetries(server.URL, true, RetryOptions{
+		Backoff:        ExponentialBackoff{Initial: time.Millisecond},
+		MaxElapsedTime: time.Second,
+	})
+	var httpError *HttpError
+	if !errors.As(err, &httpError) || httpError.StatusCode != http.StatusServiceUnavailable {
+		t.Fatalf("expected a 503 HttpError, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("expected to give up instead of waiting for Retry-After, took %v", elapsed)
+	}
+}
+
+func TestRetryStopsAtMaxAttempts(t *testing.T) {
+	attempts := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		w.Header().Set("Retry-After", "60")
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	retries := 0
+	client := &McmaHttpClient{httpClient: server.Client()}
+	_, err := client.GetWithRetries(server.URL, true, RetryOptions{
+		Backoff:          DecorrelatedJitterBackoff{Base: time.Millisecond, Max: 5 * time.Millisecond},
+		MaxAttempts:      4,
+		IgnoreRetryAfter: true,
+		OnRetry: func(attempt int, resp *http.Response, err error, delay time.Duration) {
+			retries++
+		},
+	})
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+	if attempts != 4 || retries != 3 {
+		t.Errorf("expected 4 attempts and 3 retries, got %d and %d", attempts, retries)
+	}
+}
+
+type countingAuthenticator struct {
+	mutex sync.Mutex
+	count int
+}
+
+func (a *countingAuthenticator) Authenticate(req *http.Request) error {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	a.count++
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer token-%d", a.count))
+	return nil
+}
+
+// newFailingServer returns a server that fails the first failures requests with a 503 and records what it received
+func newFailingServer(failures int) (*httptest.Server, func() ([]string, []string)) {
+	var mutex sync.Mutex
+	var bodies, authorizations []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		mutex.Lock()
+		defer mutex.Unlock()
+		bodies = append(bodies, string(body))
+		authorizations = append(authorizations, r.Header.Get("Authorization"))
+		if len(bodies) <= failures {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	return server, func() ([]string, []string) {
+		mutex.Lock()
+		defer mutex.Unlock()
+		return bodies, authorizations
+	}
+}
+
+func TestRetryReplaysBodyAndReauthenticates(t *testing.T) {
+	server, received := newFailingServer(2)
+	defer server.Close()
+
+	var authenticator Authenticator = &countingAuthenticator{}
+	client := &McmaHttpClient{httpClient: server.Client(), authenticator: &authenticator}
+	retryOpts := RetryOptions{Intervals: []time.Duration{time.Millisecond, time.Millisecond}}
+
+	body := `{"name":"test"}`
+	if _, err := client.PutWithRetries(server.URL, bytes.NewReader([]byte(body)), retryOpts); err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	bodies, authorizations := received()
+	if len(bodies) != 3 {
+		t.Fatalf("expected 3 attempts, got %d", len(bodies))
+	}
+	for i := range bodies {
+		if bodies[i] != body {
+			t.Errorf("attempt %d: expected body %s, got %q", i+1, body, bodies[i])
+		}
+		if expected := fmt.Sprintf("Bearer token-%d", i+1); authorizations[i] != expected {
+			t.Errorf("attempt %d: expected %s, got %s", i+1, expected, authorizations[i])
+		}
+	}
+}
+
+func TestRetryReplaysBodyWithAWS4Auth(t *testing.T) {
+	server, received := newFailingServer(1)
+	defer server.Close()
+
+	var authenticator Authenticator = NewAWS4AuthenticatorFromKeys("access-key", "secret-key", "", "eu-west-1")
+	client := &McmaHttpClient{httpClient: server.Client(), authenticator: &authenticator}
+	retryOpts := RetryOptions{Intervals: []time.Duration{time.Millisecond}}
+
+	key, _ := NewIdempotencyKey()
+	ctx := WithIdempotencyKey(context.Background(), key)
+	body := `{"@type":"Job"}`
+	if _, err := client.PostWithRetriesContext(ctx, server.URL, bytes.NewReader([]byte(body)), retryOpts); err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	bodies, authorizations := received()
+	if len(bodies) != 2 || bodies[0] != body || bodies[1] != body {
+		t.Errorf("expected the body on both attempts, got %q", bodies)
+	}
+	for i, authorization := range authorizations {
+		if !strings.HasPrefix(authorization, "AWS4-HMAC-SHA256") {
+			t.Errorf("attempt %d: expected an AWS4 signature, got %q", i+1, authorization)
+		}
+	}
+}
+
+type closeTrackingBody struct {
+	io.Reader
+	closed *int
+}
+
+func (b closeTrackingBody) Close() error {
+	*b.closed++
+	return nil
+}
+
+func TestRetryClosesFailedResponses(t *testing.T) {
+	closed := 0
+	attempts := 0
+	client := &http.Client{Transport: RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		attempts++
+		statusCode := http.StatusServiceUnavailable
+		if attempts == 3 {
+			statusCode = http.StatusOK
+		}
+		return &http.Response{
+			StatusCode: statusCode,
+			Body:       closeTrackingBody{Reader: strings.NewReader("body"), closed: &closed},
+			Request:    req,
+		}, nil
+	})}
+	req, _ := http.NewRequest("GET", "http://example.com", nil)
+
+	done, resp, err := ExecuteWithRetries(client, req, RetryOptions{Intervals: []time.Duration{time.Millisecond, time.Millisecond}})
+	if !done || err != nil || resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected success, got %v, %v", done, err)
+	}
+	if closed != 2 {
+		t.Errorf("expected the 2 failed responses to be closed, got %d", closed)
+	}
+}
+
+func TestRetryClosesResponseWhenContextIsDone(t *testing.T) {
+	closed := 0
+	ctx, cancel := context.WithCancel(context.Background())
+	client := &http.Client{Transport: RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		// the context ends just as the response arrives
+		cancel()
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       closeTrackingBody{Reader: strings.NewReader("body"), closed: &closed},
+			Request:    req,
+		}, nil
+	})}
+	req, _ := http.NewRequestWithContext(ctx, "GET", "http://example.com", nil)
+
+	done, resp, err := ExecuteWithRetries(client, req, RetryOptions{Intervals: []time.Duration{time.Millisecond}})
+	if done || resp != nil || !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected the cancellation without a response, got %v, %v, %v", done, resp, err)
+	}
+	if closed != 1 {
+		t.Errorf("expected the response to be closed, got %d", closed)
+	}
+}
+
+func TestRetryDoesNotReplayBodyWithoutGetBody(t *testing.T) {
+	server, received := newFailingServer(1)
+	defer server.Close()
+
+	req, _ := http.NewRequest("PUT", server.URL, io.NopCloser(strings.NewReader("body")))
+	done, resp, err := ExecuteWithRetries(server.Client(), req, RetryOptions{Intervals: []time.Duration{time.Millisecond}})
+	if !done || err != nil || resp.StatusCode != http.StatusServiceUnavailable {
+		t.Fatalf("expected the 503 to be returned without retrying, got %v, %v", done, err)
+	}
+	if bodies, _ := received(); len(bodies) != 1 {
+		t.Errorf("expected 1 attempt, got %d", len(bodies))
+	}
+}
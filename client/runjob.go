@@ -0,0 +1,154 @@
+package mcmaclient
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/ebu/mcma-libraries-go/model"
+)
+
+type RunJobOptions struct {
+	// PollIntervals are the waits between successive polls of the job. The last interval is repeated until the
+	// job reaches a terminal status or the context is done.
+	PollIntervals []time.Duration
+	// Notifications is optional. Receiving a notification for the job triggers an immediate check of its status. A
+	// notification is for the job if its source is the job id, or if it has no source and its content is the job.
+	Notifications <-chan model.Notification
+}
+
+var DefaultJobPollIntervals = []time.Duration{
+	1 * time.Second,
+	2 * time.Second,
+	5 * time.Second,
+	10 * time.Second,
+	15 * time.Second,
+	30 * time.Second,
+}
+
+var DefaultRunJobOptions = RunJobOptions{
+	PollIntervals: DefaultJobPollIntervals,
+}
+
+type JobFailedError struct {
+	Job model.Job
+}
+
+func (jobFailedError *JobFailedError) Error() string {
+	problemDetail := jobFailedError.Job.Error
+	if problemDetail == nil {
+		return fmt.Sprintf("job %s finished with status %s", jobFailedError.Job.Id, jobFailedError.Job.Status)
+	}
+	return fmt.Sprintf("job %s finished with status %s: %s: %s", jobFailedError.Job.Id, jobFailedError.Job.Status, problemDetail.Title, problemDetail.Detail)
+}
+
+func (jobFailedError *JobFailedError) ProblemDetail() *model.ProblemDetail {
+	return jobFailedError.Job.Error
+}
+
+func (resourceManager *ResourceManager) getJobResourceEndpoint(ctx context.Context) (*ResourceEndpointClient, error) {
+	if err := resourceManager.EnsureInitContext(ctx); err != nil {
+		return nil, err
+	}
+	for _, s := range resourceManager.getServices() {
+		if resourceEndpointClient, matched := s.GetResourceEndpointClientByTypeName(model.JobType); matched {
+			return resourceEndpointClient, nil
+		}
+	}
+	return nil, fmt.Errorf("no available resource endpoints for resource of type '%s'", model.JobType)
+}
+
+func (resourceManager *ResourceManager) RunJob(ctx context.Context, job interface{}, opts RunJobOptions) (model.Job, error) {
+	jobEndpoint, err := resourceManager.getJobResourceEndpoint(ctx)
+	if err != nil {
+		return model.Job{}, err
+	}
+
+	jobType := reflect.TypeOf(model.Job{})
+	created, err := jobEndpoint.PostContext(ctx, jobType, "", job)
+	if err != nil {
+		return model.Job{}, fmt.Errorf("failed to create job: %w", err)
+	}
+	currentJob := created.(model.Job)
+	if currentJob.Id == "" {
+		return currentJob, fmt.Errorf("job processor did not return an id for the created job")
+	}
+
+	pollIntervals := opts.PollIntervals
+	if len(pollIntervals) == 0 {
+		pollIntervals = DefaultJobPollIntervals
+	}
+
+	for poll := 0; !currentJob.Status.IsTerminal(); poll++ {
+		interval := pollIntervals[len(pollIntervals)-1]
+		if poll < len(pollIntervals) {
+			interval = pollIntervals[poll]
+		}
+
+		notifiedJob, err := waitForJobPoll(ctx, currentJob.Id, interval, opts.Notifications)
+		if err != nil {
+			return currentJob, err
+		}
+		if notifiedJob != nil && notifiedJob.Status.IsTerminal() {
+			currentJob = *notifiedJob
+			break
+		}
+
+		fetched, err := jobEndpoint.GetContext(ctx, jobType, currentJob.Id)
+		if err != nil {
+			return currentJob, fmt.Errorf("failed to get job %s: %w", currentJob.Id, err)
+		}
+		if fetched == nil {
+			return currentJob, fmt.Errorf("job %s not found", currentJob.Id)
+		}
+		currentJob = fetched.(model.Job)
+	}
+
+	if currentJob.Status != model.JobStatusCompleted {
+		return currentJob, &JobFailedError{Job: currentJob}
+	}
+	return currentJob, nil
+}
+
+// waitForJobPoll waits for the poll interval to elapse or for a notification about the job to arrive, whichever
+// comes first. If the notification carries the job it is returned so the caller can skip a round trip.
+func waitForJobPoll(ctx context.Context, jobId string, interval time.Duration, notifications <-chan model.Notification) (*model.Job, error) {
+	timer := time.NewTimer(interval)
+	defer timer.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-timer.C:
+			return nil, nil
+		case notification, ok := <-notifications:
+			if !ok {
+				notifications = nil
+				continue
+			}
+			// the channel may be shared by several jobs, so a notification without a source is only used if its content
+			// is this job
+			job := getJobFromNotification(notification)
+			if notification.Source == jobId || (notification.Source == "" && job != nil && job.Id == jobId) {
+				return job, nil
+			}
+		}
+	}
+}
+
+func getJobFromNotification(notification model.Notification) *model.Job {
+	if notification.Content == nil {
+		return nil
+	}
+	contentJson, err := json.Marshal(notification.Content)
+	if err != nil {
+		return nil
+	}
+	var job model.Job
+	if err := json.Unmarshal(contentJson, &job); err != nil {
+		return nil
+	}
+	return &job
+}
@@ -0,0 +1,131 @@
+package mcmaclient
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ebu/mcma-libraries-go/model"
+)
+
+func newJobProcessorServer() (*fakeMcmaServer, *ResourceManager) {
+	server := newFakeMcmaServer()
+	server.addService("job-processor", map[string]string{"Job": "/jobs"})
+	resourceManager := NewResourceManagerWithOptions(server.URL)
+	return server, resourceManager
+}
+
+func completeJobAfter(server *fakeMcmaServer, status model.JobStatus, problemDetail *model.ProblemDetail, polls int) {
+	go func() {
+		for server.countRequests("GET /jobs/") < polls {
+			time.Sleep(5 * time.Millisecond)
+		}
+		id := server.URL + "/jobs/1"
+		job := server.getResource(id)
+		updated := map[string]interface{}{}
+		for k, v := range job {
+			updated[k] = v
+		}
+		updated["status"] = string(status)
+		updated["jobOutput"] = map[string]interface{}{"outputFile": "b.json"}
+		if problemDetail != nil {
+			updated["error"] = problemDetail
+		}
+		server.putResource(id, updated)
+	}()
+}
+
+func TestRunJobPollsUntilCompleted(t *testing.T) {
+	server, resourceManager := newJobProcessorServer()
+	defer server.Close()
+	completeJobAfter(server, model.JobStatusCompleted, nil, 2)
+
+	job := model.NewAmeJob("profile", map[string]interface{}{"inputFile": "a.mp4"})
+	result, err := resourceManager.RunJob(context.Background(), job, RunJobOptions{PollIntervals: []time.Duration{10 * time.Millisecond}})
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	if result.Status != model.JobStatusCompleted || result.JobOutput["outputFile"] != "b.json" {
+		t.Errorf("unexpected job %+v", result)
+	}
+	if result.Type != model.AmeJobType {
+		t.Errorf("expected job type %s, got %s", model.AmeJobType, result.Type)
+	}
+}
+
+func TestRunJobReturnsJobFailedError(t *testing.T) {
+	server, resourceManager := newJobProcessorServer()
+	defer server.Close()
+	problemDetail := model.NewProblemDetail("uri://mcma.ebu.ch/rfc7807/job-failed", "Job failed", "input file not found")
+	completeJobAfter(server, model.JobStatusFailed, &problemDetail, 1)
+
+	_, err := resourceManager.RunJob(context.Background(), model.NewJob("profile", nil), RunJobOptions{PollIntervals: []time.Duration{10 * time.Millisecond}})
+	var jobFailedError *JobFailedError
+	if !errors.As(err, &jobFailedError) {
+		t.Fatalf("expected *JobFailedError, got %v", err)
+	}
+	if jobFailedError.ProblemDetail() == nil || jobFailedError.ProblemDetail().Detail != "input file not found" {
+		t.Errorf("unexpected problem detail %+v", jobFailedError.ProblemDetail())
+	}
+}
+
+func TestRunJobCompletesEarlyOnNotification(t *testing.T) {
+	server, resourceManager := newJobProcessorServer()
+	defer server.Close()
+
+	notifications := make(chan model.Notification, 1)
+	go func() {
+		for server.countRequests("POST /jobs") == 0 {
+			time.Sleep(5 * time.Millisecond)
+		}
+		job := model.NewJob("profile", nil)
+		job.Id = server.URL + "/jobs/1"
+		job.Status = model.JobStatusCompleted
+		notifications <- model.NewNotification(job.Id, job)
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	result, err := resourceManager.RunJob(ctx, model.NewJob("profile", nil), RunJobOptions{
+		PollIntervals: []time.Duration{time.Hour},
+		Notifications: notifications,
+	})
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	if result.Status != model.JobStatusCompleted {
+		t.Errorf("unexpected job %+v", result)
+	}
+	if server.countRequests("GET /jobs/") != 0 {
+		t.Errorf("did not expect the job to be polled")
+	}
+}
+
+func TestWaitForJobPollIgnoresNotificationsForOtherJobs(t *testing.T) {
+	jobId := "https://service/jobs/1"
+	newJobWithId := func(id string, status model.JobStatus) model.Job {
+		job := model.NewJob("profile", nil)
+		job.Id = id
+		job.Status = status
+		return job
+	}
+
+	notifications := make(chan model.Notification, 3)
+	notifications <- model.NewNotification("", newJobWithId("https://service/jobs/2", model.JobStatusFailed))
+	notifications <- model.NewNotification("https://service/jobs/2", newJobWithId("https://service/jobs/2", model.JobStatusFailed))
+	notifications <- model.NewNotification("", newJobWithId(jobId, model.JobStatusCompleted))
+
+	job, err := waitForJobPoll(context.Background(), jobId, time.Hour, notifications)
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	if job == nil || job.Id != jobId || job.Status != model.JobStatusCompleted {
+		t.Errorf("expected the notification for the job, got %+v", job)
+	}
+
+	notifications <- model.NewNotification("", newJobWithId("https://service/jobs/2", model.JobStatusFailed))
+	if job, err = waitForJobPoll(context.Background(), jobId, 20*time.Millisecond, notifications); job != nil || err != nil {
+		t.Errorf("expected the wait to run to the poll interval, got %+v, %v", job, err)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"net/http"
 	"reflect"
 	"strings"
+	"sync"
 
 	"github.com/ebu/mcma-libraries-go/model"
 )
@@ -13,11 +14,17 @@ type ServiceClient struct {
 	httpClient      *http.Client
 	service         model.Service
 	tracker         *model.McmaTracker
+	retryOptions    *RetryOptions
+	userAgent       string
+	middlewares     []Middleware
 	resources       []*ResourceEndpointClient
 	resourcesByType map[string]*ResourceEndpointClient
+	resourcesMutex  sync.Mutex
 }
 
 func (serviceClient *ServiceClient) loadResources() {
+	serviceClient.resourcesMutex.Lock()
+	defer serviceClient.resourcesMutex.Unlock()
 	if serviceClient.resourcesByType != nil {
 		return
 	}
@@ -29,6 +36,9 @@ func (serviceClient *ServiceClient) loadResources() {
 			resourceEndpoint: r,
 			serviceAuthType:  serviceClient.service.AuthType,
 			tracker:          serviceClient.tracker,
+			retryOptions:     serviceClient.retryOptions,
+			userAgent:        serviceClient.userAgent,
+			middlewares:      serviceClient.middlewares,
 		}
 		serviceClient.resources = append(serviceClient.resources, resourceEndpointClient)
 		serviceClient.resourcesByType[r.ResourceType] = resourceEndpointClient
@@ -0,0 +1,29 @@
+package mcmaclient
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/ebu/mcma-libraries-go/model"
+)
+
+func TestGetResourceEndpointClientByJobType(t *testing.T) {
+	serviceClient := &ServiceClient{
+		service: model.NewServiceNoAuth("job-processor", []model.ResourceEndpoint{
+			model.NewResourceEndpoint("Job", "https://job-processor/api/jobs"),
+			model.NewResourceEndpoint("AmeJob", "https://job-processor/api/ame-jobs"),
+		}),
+	}
+
+	resourceEndpointClient, found := serviceClient.GetResourceEndpointClientByType(reflect.TypeOf(model.AmeJob{}))
+	if !found {
+		t.Fatalf("expected resource endpoint for AmeJob")
+	}
+	if resourceEndpointClient.getHttpEndpoint() != "https://job-processor/api/ame-jobs" {
+		t.Errorf("unexpected http endpoint %s", resourceEndpointClient.getHttpEndpoint())
+	}
+
+	if _, found = serviceClient.GetResourceEndpointClientByType(reflect.TypeOf(model.TransformJob{})); found {
+		t.Errorf("did not expect resource endpoint for TransformJob")
+	}
+}
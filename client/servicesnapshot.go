@@ -0,0 +1,122 @@
+package mcmaclient
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/ebu/mcma-libraries-go/model"
+)
+
+// ServiceSnapshotStore persists the services discovered from the service registry so that a ResourceManager can
+// start from them without waiting for the registry. Load returns nil if there is no snapshot yet.
+type ServiceSnapshotStore interface {
+	Load(ctx context.Context) ([]model.Service, error)
+	Save(ctx context.Context, services []model.Service) error
+}
+
+type FileServiceSnapshotStore struct {
+	path string
+}
+
+type serviceSnapshotJson struct {
+	DateSaved time.Time       `json:"dateSaved"`
+	Services  []model.Service `json:"services"`
+}
+
+func NewFileServiceSnapshotStore(path string) *FileServiceSnapshotStore {
+	return &FileServiceSnapshotStore{
+		path: path,
+	}
+}
+
+func (store *FileServiceSnapshotStore) Load(ctx context.Context) ([]model.Service, error) {
+	data, err := os.ReadFile(store.path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read service snapshot from %s: %v", store.path, err)
+	}
+	var snapshot serviceSnapshotJson
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		return nil, fmt.Errorf("failed to parse service snapshot from %s: %v", store.path, err)
+	}
+	return snapshot.Services, nil
+}
+
+func (store *FileServiceSnapshotStore) Save(ctx context.Context, services []model.Service) error {
+	data, err := json.Marshal(&serviceSnapshotJson{
+		DateSaved: time.Now().UTC(),
+		Services:  services,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal service snapshot: %v", err)
+	}
+
+	// write to a temp file and rename it over the snapshot so a crash never leaves a partial snapshot behind
+	tmp, err := os.CreateTemp(filepath.Dir(store.path), filepath.Base(store.path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to create service snapshot in %s: %v", filepath.Dir(store.path), err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("failed to write service snapshot to %s: %v", tmp.Name(), err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write service snapshot to %s: %v", tmp.Name(), err)
+	}
+	if err := os.Rename(tmp.Name(), store.path); err != nil {
+		return fmt.Errorf("failed to save service snapshot to %s: %v", store.path, err)
+	}
+	return nil
+}
+
+func (resourceManager *ResourceManager) SetServiceSnapshotStore(snapshotStore ServiceSnapshotStore) {
+	resourceManager.servicesMutex.Lock()
+	defer resourceManager.servicesMutex.Unlock()
+	resourceManager.snapshotStore = snapshotStore
+}
+
+func (resourceManager *ResourceManager) getSnapshotStore() ServiceSnapshotStore {
+	resourceManager.servicesMutex.RLock()
+	defer resourceManager.servicesMutex.RUnlock()
+	return resourceManager.snapshotStore
+}
+
+// initFromSnapshot loads services from the snapshot store, if there is one, and reconciles them with the service
+// registry in the background. It returns false if no services could be loaded.
+func (resourceManager *ResourceManager) initFromSnapshot(ctx context.Context) bool {
+	snapshotStore := resourceManager.getSnapshotStore()
+	if snapshotStore == nil {
+		return false
+	}
+	services, err := snapshotStore.Load(ctx)
+	if err != nil {
+		resourceManager.logf("failed to load service snapshot: %v", err)
+		return false
+	}
+	if len(services) == 0 {
+		return false
+	}
+	resourceManager.setServices(services, time.Time{})
+	resourceManager.refreshInBackground()
+	return true
+}
+
+func (resourceManager *ResourceManager) saveSnapshot(ctx context.Context, services []model.Service) {
+	snapshotStore := resourceManager.getSnapshotStore()
+	if snapshotStore == nil {
+		return
+	}
+	// the snapshot is only an optimization for cold starts, so failing to save it must not fail the caller
+	if err := snapshotStore.Save(ctx, services); err != nil {
+		resourceManager.logf("failed to save service snapshot: %v", err)
+	}
+}
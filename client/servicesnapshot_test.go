@@ -0,0 +1,55 @@
+package mcmaclient
+
+import (
+	"context"
+	"net/http"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ebu/mcma-libraries-go/model"
+)
+
+func TestColdStartFromServiceSnapshot(t *testing.T) {
+	server := newFakeMcmaServer()
+	defer server.Close()
+	server.addService("job-processor", map[string]string{"Job": "/jobs"})
+
+	snapshotStore := NewFileServiceSnapshotStore(filepath.Join(t.TempDir(), "services.json"))
+	if services, err := snapshotStore.Load(context.Background()); err != nil || services != nil {
+		t.Fatalf("expected no snapshot, got %v, %v", services, err)
+	}
+
+	warm := NewResourceManagerWithOptions(server.URL)
+	warm.SetServiceSnapshotStore(snapshotStore)
+	if err := warm.EnsureInit(); err != nil {
+		t.Fatalf("%v", err)
+	}
+	saved, err := snapshotStore.Load(context.Background())
+	if err != nil || len(saved) != 1 || saved[0].Name != "job-processor" {
+		t.Fatalf("expected snapshot with the job processor, got %+v, %v", saved, err)
+	}
+
+	// the registry is now down, so a cold start can only succeed from the snapshot
+	server.handle("GET", "/services", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	})
+	server.addService("job-processor-2", map[string]string{"Job": "/jobs-2"})
+
+	cold := NewResourceManagerWithOptions(server.URL)
+	cold.SetServiceSnapshotStore(snapshotStore)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	start := time.Now()
+	jobs, err := QueryContext[model.Job](ctx, cold, nil)
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	if len(jobs) != 0 || time.Since(start) > 500*time.Millisecond {
+		t.Errorf("expected a fast query from the snapshot, got %d jobs in %v", len(jobs), time.Since(start))
+	}
+	if len(cold.getServices()) != 2 {
+		t.Errorf("expected the registry and the job processor from the snapshot, got %d services", len(cold.getServices()))
+	}
+	cold.Invalidate()
+}
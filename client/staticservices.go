@@ -0,0 +1,79 @@
+package mcmaclient
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/ebu/mcma-libraries-go/model"
+)
+
+type servicesManifestJson struct {
+	Services []model.Service `json:"services"`
+}
+
+// ParseServicesManifest parses a list of services from JSON or YAML. The manifest can either be a list of services
+// or an object with the list in a services property.
+func ParseServicesManifest(data []byte) ([]model.Service, error) {
+	// YAML is a superset of JSON, so both are parsed as YAML and converted to JSON to go through the model's
+	// own unmarshalling
+	var manifest interface{}
+	if err := yaml.Unmarshal(data, &manifest); err != nil {
+		return nil, fmt.Errorf("failed to parse services manifest: %v", err)
+	}
+	manifestJson, err := json.Marshal(manifest)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert services manifest to json: %v", err)
+	}
+
+	if _, isList := manifest.([]interface{}); isList {
+		var services []model.Service
+		if err := json.Unmarshal(manifestJson, &services); err != nil {
+			return nil, fmt.Errorf("failed to parse services in manifest: %v", err)
+		}
+		return services, nil
+	}
+
+	var tmp servicesManifestJson
+	if err := json.Unmarshal(manifestJson, &tmp); err != nil {
+		return nil, fmt.Errorf("failed to parse services in manifest: %v", err)
+	}
+	return tmp.Services, nil
+}
+
+func LoadServicesManifest(path string) ([]model.Service, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read services manifest %s: %v", path, err)
+	}
+	return ParseServicesManifest(data)
+}
+
+// SetStaticServices makes the resource manager route requests to the given services instead of querying the
+// service registry
+func (resourceManager *ResourceManager) SetStaticServices(services []model.Service) {
+	resourceManager.servicesMutex.Lock()
+	resourceManager.staticServices = append([]model.Service{}, services...)
+	resourceManager.servicesMutex.Unlock()
+	resourceManager.Invalidate()
+}
+
+func (resourceManager *ResourceManager) getStaticServices() []model.Service {
+	resourceManager.servicesMutex.RLock()
+	defer resourceManager.servicesMutex.RUnlock()
+	return resourceManager.staticServices
+}
+
+func NewResourceManagerFromServices(services []model.Service, opts ...Option) *ResourceManager {
+	return NewResourceManagerWithOptions("", append([]Option{WithServices(services)}, opts...)...)
+}
+
+func NewResourceManagerFromManifest(path string, opts ...Option) (*ResourceManager, error) {
+	services, err := LoadServicesManifest(path)
+	if err != nil {
+		return nil, err
+	}
+	return NewResourceManagerFromServices(services, opts...), nil
+}
@@ -0,0 +1,68 @@
+package mcmaclient
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ebu/mcma-libraries-go/model"
+)
+
+func TestStaticResourceManagerSkipsRegistry(t *testing.T) {
+	server := newFakeMcmaServer()
+	defer server.Close()
+	service := server.addService("job-processor", map[string]string{"Job": "/jobs"})
+	server.putResource(server.URL+"/jobs/1", map[string]interface{}{"@type": "Job", "id": server.URL + "/jobs/1"})
+
+	resourceManager := NewResourceManagerFromServices([]model.Service{service})
+	jobs, err := Query[model.Job](resourceManager, nil)
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	if len(jobs) != 1 {
+		t.Errorf("expected 1 job, got %d", len(jobs))
+	}
+	if count := server.countRequests("GET /services"); count != 0 {
+		t.Errorf("expected the service registry not to be queried, got %d requests", count)
+	}
+
+	job, err := Get[model.Job](resourceManager, server.URL+"/jobs/1")
+	if err != nil || job == nil {
+		t.Fatalf("expected the job to be routed to the static service, got %v, %v", job, err)
+	}
+}
+
+func TestParseServicesManifest(t *testing.T) {
+	manifests := map[string]string{
+		"services.json": `[{"name": "job-processor", "authType": "AWS4", "resources": [{"resourceType": "Job", "httpEndpoint": "https://example.com/jobs"}]}]`,
+		"services.yaml": `
+services:
+  - name: job-processor
+    authType: AWS4
+    resources:
+      - resourceType: Job
+        httpEndpoint: https://example.com/jobs
+`,
+	}
+	dir := t.TempDir()
+	for name, manifest := range manifests {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(manifest), 0644); err != nil {
+			t.Fatalf("%v", err)
+		}
+		services, err := LoadServicesManifest(path)
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if len(services) != 1 || services[0].Name != "job-processor" || services[0].AuthType != "AWS4" {
+			t.Fatalf("%s: unexpected services %+v", name, services)
+		}
+		if len(services[0].Resources) != 1 || services[0].Resources[0].HttpEndpoint != "https://example.com/jobs" {
+			t.Errorf("%s: unexpected resources %+v", name, services[0].Resources)
+		}
+	}
+
+	if _, err := ParseServicesManifest([]byte("services: [")); err == nil {
+		t.Error("expected an error for an invalid manifest")
+	}
+}
@@ -0,0 +1,192 @@
+package mcmaclient
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// defaultTokenLifetime is used for tokens that do not say when they expire
+const defaultTokenLifetime = 5 * time.Minute
+
+const defaultTokenRefreshBefore = time.Minute
+
+// tokenFetchTimeout bounds a fetch, which is shared by all waiting callers and so does not use their contexts
+const tokenFetchTimeout = time.Minute
+
+type cachedToken struct {
+	value     string
+	tokenType string
+	issuedAt  time.Time
+	expiresAt time.Time
+}
+
+// needsRefresh returns whether the token is within refreshBefore of expiring. The window is capped at half the
+// lifetime of the token so that short-lived tokens are not refreshed on every use.
+func (token *cachedToken) needsRefresh(now time.Time, refreshBefore time.Duration) bool {
+	if lifetime := token.expiresAt.Sub(token.issuedAt); lifetime > 0 && refreshBefore > lifetime/2 {
+		refreshBefore = lifetime / 2
+	}
+	return now.Add(refreshBefore).After(token.expiresAt)
+}
+
+type tokenFetch struct {
+	done  chan struct{}
+	token cachedToken
+	err   error
+}
+
+// tokenCache holds a token from fetch until it expires. Once the token is within refreshBefore of expiring it is
+// still returned while a new one is fetched in the background, so callers do not wait for the refresh. Concurrent
+// callers without a valid token share a single fetch, and each stops waiting when its own context is done.
+type tokenCache struct {
+	fetch         func(ctx context.Context) (cachedToken, error)
+	refreshBefore time.Duration
+	mutex         sync.Mutex
+	token         *cachedToken
+	inFlight      *tokenFetch
+}
+
+func newTokenCache(refreshBefore time.Duration, fetch func(ctx context.Context) (cachedToken, error)) *tokenCache {
+	if refreshBefore <= 0 {
+		refreshBefore = defaultTokenRefreshBefore
+	}
+	return &tokenCache{
+		fetch:         fetch,
+		refreshBefore: refreshBefore,
+	}
+}
+
+func (cache *tokenCache) get(ctx context.Context) (cachedToken, error) {
+	cache.mutex.Lock()
+	now := time.Now()
+	if cache.token != nil && now.Before(cache.token.expiresAt) {
+		token := *cache.token
+		if cache.inFlight == nil && token.needsRefresh(now, cache.refreshBefore) {
+			cache.startFetch()
+		}
+		cache.mutex.Unlock()
+		return token, nil
+	}
+	fetch := cache.inFlight
+	if fetch == nil {
+		fetch = cache.startFetch()
+	}
+	cache.mutex.Unlock()
+
+	select {
+	case <-fetch.done:
+		return fetch.token, fetch.err
+	case <-ctx.Done():
+		return cachedToken{}, ctx.Err()
+	}
+}
+
+// startFetch fetches a new token in the background. It must be called with the mutex held.
+func (cache *tokenCache) startFetch() *tokenFetch {
+	fetch := &tokenFetch{done: make(chan struct{})}
+	cache.inFlight = fetch
+
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), tokenFetchTimeout)
+		defer cancel()
+		issuedAt := time.Now()
+		token, err := cache.fetch(ctx)
+		if err == nil && token.issuedAt.IsZero() {
+			token.issuedAt = issuedAt
+		}
+
+		cache.mutex.Lock()
+		cache.inFlight = nil
+		// a failed refresh is retried on next use, and the current token keeps being used until it expires
+		if err == nil {
+			cache.token = &token
+		}
+		cache.mutex.Unlock()
+
+		fetch.token, fetch.err = token, err
+		close(fetch.done)
+	}()
+	return fetch
+}
+
+type tokenResponseJson struct {
+	AccessToken      string      `json:"access_token"`
+	IdToken          string      `json:"id_token"`
+	TokenType        string      `json:"token_type"`
+	ExpiresIn        interface{} `json:"expires_in"`
+	ExpiresOn        interface{} `json:"expires_on"`
+	Error            string      `json:"error"`
+	ErrorDescription string      `json:"error_description"`
+}
+
+// expiresAt returns when the token expires from expires_in or expires_on, which some token endpoints return as
+// strings rather than numbers
+func (tokenResponse *tokenResponseJson) expiresAt(issuedAt time.Time) time.Time {
+	if expiresIn, ok := parseTokenNumber(tokenResponse.ExpiresIn); ok {
+		return issuedAt.Add(time.Duration(expiresIn) * time.Second)
+	}
+	if expiresOn, ok := parseTokenNumber(tokenResponse.ExpiresOn); ok {
+		return time.Unix(expiresOn, 0)
+	}
+	return issuedAt.Add(defaultTokenLifetime)
+}
+
+func parseTokenNumber(value interface{}) (int64, bool) {
+	switch v := value.(type) {
+	case float64:
+		return int64(v), true
+	case string:
+		n, err := strconv.ParseInt(v, 10, 64)
+		return n, err == nil
+	}
+	return 0, false
+}
+
+// requestToken posts a form to a token endpoint and parses the token response
+func requestToken(ctx context.Context, httpClient *http.Client, tokenUrl string, form url.Values, prepare func(req *http.Request)) (*tokenResponseJson, error) {
+	req, err := http.NewRequestWithContext(ctx, "POST", tokenUrl, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	if prepare != nil {
+		prepare(req)
+	}
+	return doTokenRequest(httpClient, req)
+}
+
+func doTokenRequest(httpClient *http.Client, req *http.Request) (*tokenResponseJson, error) {
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to request token from %s: %w", req.URL.Redacted(), err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read token response from %s: %v", req.URL.Redacted(), err)
+	}
+	var tokenResponse tokenResponseJson
+	if err := json.Unmarshal(body, &tokenResponse); err != nil && resp.StatusCode < 400 {
+		return nil, fmt.Errorf("failed to parse token response from %s: %v", req.URL.Redacted(), err)
+	}
+	if resp.StatusCode >= 400 || tokenResponse.Error != "" {
+		if tokenResponse.Error != "" {
+			return nil, fmt.Errorf("token request to %s failed with %s: %s", req.URL.Redacted(), tokenResponse.Error, tokenResponse.ErrorDescription)
+		}
+		return nil, fmt.Errorf("token request to %s failed with status %s", req.URL.Redacted(), resp.Status)
+	}
+	return &tokenResponse, nil
+}
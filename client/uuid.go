@@ -0,0 +1,17 @@
+package mcmaclient
+
+import (
+	"crypto/rand"
+	"fmt"
+)
+
+// newUuid generates a random version 4 uuid, used for idempotency keys and the ids of client assertion JWTs
+func newUuid() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:]), nil
+}
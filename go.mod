@@ -2,6 +2,9 @@ module github.com/ebu/mcma-libraries-go
 
 go 1.20
 
-require github.com/aws/aws-sdk-go v1.44.322
+require (
+	github.com/aws/aws-sdk-go v1.44.322
+	gopkg.in/yaml.v3 v3.0.1
+)
 
 require github.com/jmespath/go-jmespath v0.4.0 // indirect
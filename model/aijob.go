@@ -0,0 +1,43 @@
+package model
+
+type AIJob struct {
+	Job
+}
+
+var AIJobType = "AIJob"
+
+func NewAIJob(jobProfileId string, jobInput map[string]interface{}) AIJob {
+	return AIJob{
+		Job: Job{
+			Type:         AIJobType,
+			JobProfileId: jobProfileId,
+			JobInput:     jobInput,
+		},
+	}
+}
+
+func NewAIJobWithNotificationEndpoint(jobProfileId string, jobInput map[string]interface{}, notificationEndpoint NotificationEndpoint) AIJob {
+	return AIJob{
+		Job: Job{
+			Type:                 AIJobType,
+			JobProfileId:         jobProfileId,
+			JobInput:             jobInput,
+			NotificationEndpoint: &notificationEndpoint,
+		},
+	}
+}
+
+func (j AIJob) MarshalJSON() ([]byte, error) {
+	j.Type = AIJobType
+	return j.Job.MarshalJSON()
+}
+
+func (j *AIJob) UnmarshalJSON(data []byte) error {
+	if err := j.Job.UnmarshalJSON(data); err != nil {
+		return err
+	}
+
+	j.Type = AIJobType
+
+	return nil
+}
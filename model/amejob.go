@@ -0,0 +1,43 @@
+package model
+
+type AmeJob struct {
+	Job
+}
+
+var AmeJobType = "AmeJob"
+
+func NewAmeJob(jobProfileId string, jobInput map[string]interface{}) AmeJob {
+	return AmeJob{
+		Job: Job{
+			Type:         AmeJobType,
+			JobProfileId: jobProfileId,
+			JobInput:     jobInput,
+		},
+	}
+}
+
+func NewAmeJobWithNotificationEndpoint(jobProfileId string, jobInput map[string]interface{}, notificationEndpoint NotificationEndpoint) AmeJob {
+	return AmeJob{
+		Job: Job{
+			Type:                 AmeJobType,
+			JobProfileId:         jobProfileId,
+			JobInput:             jobInput,
+			NotificationEndpoint: &notificationEndpoint,
+		},
+	}
+}
+
+func (j AmeJob) MarshalJSON() ([]byte, error) {
+	j.Type = AmeJobType
+	return j.Job.MarshalJSON()
+}
+
+func (j *AmeJob) UnmarshalJSON(data []byte) error {
+	if err := j.Job.UnmarshalJSON(data); err != nil {
+		return err
+	}
+
+	j.Type = AmeJobType
+
+	return nil
+}
@@ -0,0 +1,43 @@
+package model
+
+type CaptureJob struct {
+	Job
+}
+
+var CaptureJobType = "CaptureJob"
+
+func NewCaptureJob(jobProfileId string, jobInput map[string]interface{}) CaptureJob {
+	return CaptureJob{
+		Job: Job{
+			Type:         CaptureJobType,
+			JobProfileId: jobProfileId,
+			JobInput:     jobInput,
+		},
+	}
+}
+
+func NewCaptureJobWithNotificationEndpoint(jobProfileId string, jobInput map[string]interface{}, notificationEndpoint NotificationEndpoint) CaptureJob {
+	return CaptureJob{
+		Job: Job{
+			Type:                 CaptureJobType,
+			JobProfileId:         jobProfileId,
+			JobInput:             jobInput,
+			NotificationEndpoint: &notificationEndpoint,
+		},
+	}
+}
+
+func (j CaptureJob) MarshalJSON() ([]byte, error) {
+	j.Type = CaptureJobType
+	return j.Job.MarshalJSON()
+}
+
+func (j *CaptureJob) UnmarshalJSON(data []byte) error {
+	if err := j.Job.UnmarshalJSON(data); err != nil {
+		return err
+	}
+
+	j.Type = CaptureJobType
+
+	return nil
+}
@@ -0,0 +1,43 @@
+package model
+
+type DistributionJob struct {
+	Job
+}
+
+var DistributionJobType = "DistributionJob"
+
+func NewDistributionJob(jobProfileId string, jobInput map[string]interface{}) DistributionJob {
+	return DistributionJob{
+		Job: Job{
+			Type:         DistributionJobType,
+			JobProfileId: jobProfileId,
+			JobInput:     jobInput,
+		},
+	}
+}
+
+func NewDistributionJobWithNotificationEndpoint(jobProfileId string, jobInput map[string]interface{}, notificationEndpoint NotificationEndpoint) DistributionJob {
+	return DistributionJob{
+		Job: Job{
+			Type:                 DistributionJobType,
+			JobProfileId:         jobProfileId,
+			JobInput:             jobInput,
+			NotificationEndpoint: &notificationEndpoint,
+		},
+	}
+}
+
+func (j DistributionJob) MarshalJSON() ([]byte, error) {
+	j.Type = DistributionJobType
+	return j.Job.MarshalJSON()
+}
+
+func (j *DistributionJob) UnmarshalJSON(data []byte) error {
+	if err := j.Job.UnmarshalJSON(data); err != nil {
+		return err
+	}
+
+	j.Type = DistributionJobType
+
+	return nil
+}
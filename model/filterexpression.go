@@ -0,0 +1,94 @@
+package model
+
+import "encoding/json"
+
+type FilterOperator string
+
+const (
+	FilterOperatorEqual              FilterOperator = "="
+	FilterOperatorNotEqual           FilterOperator = "!="
+	FilterOperatorLessThan           FilterOperator = "<"
+	FilterOperatorLessThanOrEqual    FilterOperator = "<="
+	FilterOperatorGreaterThan        FilterOperator = ">"
+	FilterOperatorGreaterThanOrEqual FilterOperator = ">="
+)
+
+type LogicalOperator string
+
+const (
+	LogicalOperatorAnd LogicalOperator = "&&"
+	LogicalOperatorOr  LogicalOperator = "||"
+)
+
+// FilterExpression is either a FilterCriteria or a FilterCriteriaGroup
+type FilterExpression interface {
+	isFilterExpression()
+}
+
+type FilterCriteria struct {
+	PropertyName  string
+	Operator      FilterOperator
+	PropertyValue interface{}
+}
+
+type filterCriteriaJson struct {
+	PropertyName  *string        `json:"propertyName"`
+	Operator      FilterOperator `json:"operator"`
+	PropertyValue interface{}    `json:"propertyValue"`
+}
+
+type FilterCriteriaGroup struct {
+	LogicalOperator LogicalOperator
+	Children        []FilterExpression
+}
+
+type filterCriteriaGroupJson struct {
+	LogicalOperator LogicalOperator    `json:"logicalOperator"`
+	Children        []FilterExpression `json:"children"`
+}
+
+func NewFilterCriteria(propertyName string, operator FilterOperator, propertyValue interface{}) FilterCriteria {
+	return FilterCriteria{
+		PropertyName:  propertyName,
+		Operator:      operator,
+		PropertyValue: propertyValue,
+	}
+}
+
+func NewFilterCriteriaGroup(logicalOperator LogicalOperator, children ...FilterExpression) FilterCriteriaGroup {
+	return FilterCriteriaGroup{
+		LogicalOperator: logicalOperator,
+		Children:        children,
+	}
+}
+
+func (FilterCriteria) isFilterExpression() {}
+
+func (FilterCriteriaGroup) isFilterExpression() {}
+
+func (fc FilterCriteria) MarshalJSON() ([]byte, error) {
+	operator := fc.Operator
+	if len(operator) == 0 {
+		operator = FilterOperatorEqual
+	}
+	return json.Marshal(&filterCriteriaJson{
+		PropertyName:  stringPtrOrNull(fc.PropertyName),
+		Operator:      operator,
+		PropertyValue: fc.PropertyValue,
+	})
+}
+
+func (fcg FilterCriteriaGroup) MarshalJSON() ([]byte, error) {
+	logicalOperator := fcg.LogicalOperator
+	if len(logicalOperator) == 0 {
+		logicalOperator = LogicalOperatorAnd
+	}
+	children := fcg.Children
+	if children == nil {
+		children = []FilterExpression{}
+	}
+	return json.Marshal(&filterCriteriaGroupJson{
+		LogicalOperator: logicalOperator,
+		Children:        children,
+	})
+}
@@ -0,0 +1,115 @@
+package model
+
+import (
+	"encoding/json"
+	"time"
+)
+
+type Job struct {
+	Type                 string
+	Id                   string
+	DateCreated          time.Time
+	DateModified         time.Time
+	ParentId             string
+	JobProfileId         string
+	JobInput             map[string]interface{}
+	JobOutput            map[string]interface{}
+	Status               JobStatus
+	Error                *ProblemDetail
+	Progress             float64
+	NotificationEndpoint *NotificationEndpoint
+	Tracker              *McmaTracker
+	Custom               map[string]interface{}
+	AdditionalProperties map[string]interface{}
+}
+
+type jobJson struct {
+	Type                 *string                `json:"@type"`
+	Id                   *string                `json:"id"`
+	DateCreated          time.Time              `json:"dateCreated"`
+	DateModified         time.Time              `json:"dateModified"`
+	ParentId             *string                `json:"parentId"`
+	JobProfileId         *string                `json:"jobProfileId"`
+	JobInput             map[string]interface{} `json:"jobInput"`
+	JobOutput            map[string]interface{} `json:"jobOutput"`
+	Status               *JobStatus             `json:"status"`
+	Error                *ProblemDetail         `json:"error"`
+	Progress             *float64               `json:"progress"`
+	NotificationEndpoint *NotificationEndpoint  `json:"notificationEndpoint"`
+	Tracker              *McmaTracker           `json:"tracker"`
+	Custom               map[string]interface{} `json:"custom"`
+}
+
+var JobType = "Job"
+
+func NewJob(jobProfileId string, jobInput map[string]interface{}) Job {
+	return Job{
+		Type:         JobType,
+		JobProfileId: jobProfileId,
+		JobInput:     jobInput,
+	}
+}
+
+func NewJobWithNotificationEndpoint(jobProfileId string, jobInput map[string]interface{}, notificationEndpoint NotificationEndpoint) Job {
+	return Job{
+		Type:                 JobType,
+		JobProfileId:         jobProfileId,
+		JobInput:             jobInput,
+		NotificationEndpoint: &notificationEndpoint,
+	}
+}
+
+func (j Job) MarshalJSON() ([]byte, error) {
+	jobType := j.Type
+	if len(jobType) == 0 {
+		jobType = JobType
+	}
+	return marshalWithAdditionalProperties(&jobJson{
+		Type:                 &jobType,
+		Id:                   stringPtrOrNull(j.Id),
+		DateCreated:          j.DateCreated,
+		DateModified:         j.DateModified,
+		ParentId:             stringPtrOrNull(j.ParentId),
+		JobProfileId:         stringPtrOrNull(j.JobProfileId),
+		JobInput:             j.JobInput,
+		JobOutput:            j.JobOutput,
+		Status:               jobStatusPtrOrNull(j.Status),
+		Error:                j.Error,
+		Progress:             float64PtrOrNull(j.Progress),
+		NotificationEndpoint: j.NotificationEndpoint,
+		Tracker:              j.Tracker,
+		Custom:               j.Custom,
+	}, j.AdditionalProperties)
+}
+
+func (j *Job) UnmarshalJSON(data []byte) error {
+	var tmp jobJson
+	if err := json.Unmarshal(data, &tmp); err != nil {
+		return err
+	}
+	additionalProperties, err := getAdditionalProperties(data, &tmp)
+	if err != nil {
+		return err
+	}
+
+	j.Type = stringOrEmpty(tmp.Type)
+	if len(j.Type) == 0 {
+		j.Type = JobType
+	}
+	j.Id = stringOrEmpty(tmp.Id)
+	j.DateCreated = tmp.DateCreated
+	j.DateModified = tmp.DateModified
+	j.ParentId = stringOrEmpty(tmp.ParentId)
+	j.JobProfileId = stringOrEmpty(tmp.JobProfileId)
+	j.JobInput = tmp.JobInput
+	j.JobOutput = tmp.JobOutput
+	j.Status = jobStatusOrEmpty(tmp.Status)
+	j.Error = tmp.Error
+	j.Progress = float64OrZero(tmp.Progress)
+	j.NotificationEndpoint = tmp.NotificationEndpoint
+	j.Tracker = tmp.Tracker
+	j.Custom = tmp.Custom
+	j.AdditionalProperties = additionalProperties
+
+	return nil
+}
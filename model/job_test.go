@@ -0,0 +1,134 @@
+package model
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestJobMarshalJSON(t *testing.T) {
+	j := NewJobWithNotificationEndpoint(
+		"https://service-registry/api/job-profiles/1",
+		map[string]interface{}{
+			"inputFile": NewLocator("https://s3/bucket/input.mp4"),
+		},
+		NewNotificationEndpoint("", "https://client/notifications"))
+	data, err := json.Marshal(j)
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	t.Log(string(data))
+
+	var m map[string]interface{}
+	if err := json.Unmarshal(data, &m); err != nil {
+		t.Fatalf("%v", err)
+	}
+	if m["@type"] != "Job" {
+		t.Errorf("expected @type Job, got %v", m["@type"])
+	}
+	if m["jobProfileId"] != "https://service-registry/api/job-profiles/1" {
+		t.Errorf("unexpected jobProfileId %v", m["jobProfileId"])
+	}
+}
+
+func TestJobUnmarshalJSON(t *testing.T) {
+	j := `{
+		"@type": "Job",
+		"id": "https://job-processor/api/jobs/1",
+		"parentId": "https://job-processor/api/jobs/0",
+		"jobProfileId": "https://service-registry/api/job-profiles/1",
+		"jobInput": { "@type": "JobParameterBag", "inputFile": { "@type": "Locator", "url": "https://s3/bucket/input.mp4" } },
+		"jobOutput": { "@type": "JobParameterBag", "outputFile": { "@type": "Locator", "url": "https://s3/bucket/output.json" } },
+		"status": "Running",
+		"progress": 42.5,
+		"tracker": { "@type": "McmaTracker", "id": "1", "label": "test" },
+		"dateCreated": "2023-01-02T03:04:05Z"
+	}`
+	job := &Job{}
+	if err := json.Unmarshal([]byte(j), job); err != nil {
+		t.Fatalf("%v", err)
+	}
+	if job.Type != JobType || job.Id != "https://job-processor/api/jobs/1" || job.ParentId != "https://job-processor/api/jobs/0" {
+		t.Errorf("unexpected job identity: %+v", job)
+	}
+	if job.Status != "Running" || job.Progress != 42.5 {
+		t.Errorf("unexpected job status: %s %v", job.Status, job.Progress)
+	}
+	if job.Tracker == nil || job.Tracker.Label != "test" {
+		t.Errorf("unexpected tracker: %+v", job.Tracker)
+	}
+	if job.JobOutput["outputFile"] == nil {
+		t.Errorf("expected outputFile in job output")
+	}
+	if job.DateCreated.Year() != 2023 {
+		t.Errorf("unexpected dateCreated %v", job.DateCreated)
+	}
+}
+
+func TestJobExecutionRoundTrip(t *testing.T) {
+	je := NewJobExecution("https://service/api/job-assignments/1")
+	je.Status = "Completed"
+	je.ActualDuration = 1500
+	data, err := json.Marshal(je)
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	var out JobExecution
+	if err := json.Unmarshal(data, &out); err != nil {
+		t.Fatalf("%v", err)
+	}
+	if out.JobAssignmentId != je.JobAssignmentId || out.Status != je.Status || out.ActualDuration != je.ActualDuration {
+		t.Errorf("round trip mismatch: %+v != %+v", out, je)
+	}
+	if !out.ActualStartDate.IsZero() {
+		t.Errorf("expected zero actualStartDate, got %v", out.ActualStartDate)
+	}
+}
+
+func TestJobSubtypeMarshalJSON(t *testing.T) {
+	jobs := []struct {
+		expectedType string
+		job          interface{}
+	}{
+		{AmeJobType, NewAmeJob("profile", nil)},
+		{TransformJobType, NewTransformJob("profile", nil)},
+		{AIJobType, NewAIJob("profile", nil)},
+		{QAJobType, NewQAJob("profile", nil)},
+		{WorkflowJobType, NewWorkflowJob("profile", nil)},
+		{CaptureJobType, NewCaptureJob("profile", nil)},
+		{DistributionJobType, NewDistributionJob("profile", nil)},
+		// the discriminator is written even when the type was not set by a constructor
+		{AmeJobType, AmeJob{}},
+	}
+	for _, tc := range jobs {
+		data, err := json.Marshal(tc.job)
+		if err != nil {
+			t.Fatalf("%v", err)
+		}
+		var m map[string]interface{}
+		if err := json.Unmarshal(data, &m); err != nil {
+			t.Fatalf("%v", err)
+		}
+		if m["@type"] != tc.expectedType {
+			t.Errorf("expected @type %s, got %v", tc.expectedType, m["@type"])
+		}
+	}
+}
+
+func TestJobSubtypeUnmarshalJSON(t *testing.T) {
+	j := `{ "@type": "AIJob", "id": "https://job-processor/api/jobs/2", "jobProfileId": "profile", "status": "Completed" }`
+	job := &AIJob{}
+	if err := json.Unmarshal([]byte(j), job); err != nil {
+		t.Fatalf("%v", err)
+	}
+	if job.Type != AIJobType || job.Id != "https://job-processor/api/jobs/2" || job.Status != "Completed" {
+		t.Errorf("unexpected job: %+v", job)
+	}
+
+	var base Job
+	if err := json.Unmarshal([]byte(j), &base); err != nil {
+		t.Fatalf("%v", err)
+	}
+	if base.Type != AIJobType {
+		t.Errorf("expected base job to keep @type %s, got %s", AIJobType, base.Type)
+	}
+}
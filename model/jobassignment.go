@@ -0,0 +1,98 @@
+package model
+
+import (
+	"encoding/json"
+	"time"
+)
+
+type JobAssignment struct {
+	Type                 string
+	Id                   string
+	DateCreated          time.Time
+	DateModified         time.Time
+	JobId                string
+	Status               JobStatus
+	Error                *ProblemDetail
+	Progress             float64
+	JobOutput            map[string]interface{}
+	NotificationEndpoint *NotificationEndpoint
+	Tracker              *McmaTracker
+	Custom               map[string]interface{}
+	AdditionalProperties map[string]interface{}
+}
+
+type jobAssignmentJson struct {
+	Type                 *string                `json:"@type"`
+	Id                   *string                `json:"id"`
+	DateCreated          time.Time              `json:"dateCreated"`
+	DateModified         time.Time              `json:"dateModified"`
+	JobId                *string                `json:"jobId"`
+	Status               *JobStatus             `json:"status"`
+	Error                *ProblemDetail         `json:"error"`
+	Progress             *float64               `json:"progress"`
+	JobOutput            map[string]interface{} `json:"jobOutput"`
+	NotificationEndpoint *NotificationEndpoint  `json:"notificationEndpoint"`
+	Tracker              *McmaTracker           `json:"tracker"`
+	Custom               map[string]interface{} `json:"custom"`
+}
+
+var JobAssignmentType = "JobAssignment"
+
+func NewJobAssignment(jobId string) JobAssignment {
+	return JobAssignment{
+		Type:  JobAssignmentType,
+		JobId: jobId,
+	}
+}
+
+func NewJobAssignmentWithNotificationEndpoint(jobId string, notificationEndpoint NotificationEndpoint) JobAssignment {
+	return JobAssignment{
+		Type:                 JobAssignmentType,
+		JobId:                jobId,
+		NotificationEndpoint: &notificationEndpoint,
+	}
+}
+
+func (ja JobAssignment) MarshalJSON() ([]byte, error) {
+	return marshalWithAdditionalProperties(&jobAssignmentJson{
+		Type:                 &JobAssignmentType,
+		Id:                   stringPtrOrNull(ja.Id),
+		DateCreated:          ja.DateCreated,
+		DateModified:         ja.DateModified,
+		JobId:                stringPtrOrNull(ja.JobId),
+		Status:               jobStatusPtrOrNull(ja.Status),
+		Error:                ja.Error,
+		Progress:             float64PtrOrNull(ja.Progress),
+		JobOutput:            ja.JobOutput,
+		NotificationEndpoint: ja.NotificationEndpoint,
+		Tracker:              ja.Tracker,
+		Custom:               ja.Custom,
+	}, ja.AdditionalProperties)
+}
+
+func (ja *JobAssignment) UnmarshalJSON(data []byte) error {
+	var tmp jobAssignmentJson
+	if err := json.Unmarshal(data, &tmp); err != nil {
+		return err
+	}
+	additionalProperties, err := getAdditionalProperties(data, &tmp)
+	if err != nil {
+		return err
+	}
+
+	ja.Type = JobAssignmentType
+	ja.Id = stringOrEmpty(tmp.Id)
+	ja.DateCreated = tmp.DateCreated
+	ja.DateModified = tmp.DateModified
+	ja.JobId = stringOrEmpty(tmp.JobId)
+	ja.Status = jobStatusOrEmpty(tmp.Status)
+	ja.Error = tmp.Error
+	ja.Progress = float64OrZero(tmp.Progress)
+	ja.JobOutput = tmp.JobOutput
+	ja.NotificationEndpoint = tmp.NotificationEndpoint
+	ja.Tracker = tmp.Tracker
+	ja.Custom = tmp.Custom
+	ja.AdditionalProperties = additionalProperties
+
+	return nil
+}
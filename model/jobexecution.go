@@ -0,0 +1,94 @@
+package model
+
+import (
+	"encoding/json"
+	"time"
+)
+
+type JobExecution struct {
+	Type                 string
+	Id                   string
+	DateCreated          time.Time
+	DateModified         time.Time
+	JobAssignmentId      string
+	Status               JobStatus
+	Error                *ProblemDetail
+	Progress             float64
+	JobOutput            map[string]interface{}
+	ActualStartDate      time.Time
+	ActualEndDate        time.Time
+	ActualDuration       int64
+	Custom               map[string]interface{}
+	AdditionalProperties map[string]interface{}
+}
+
+type jobExecutionJson struct {
+	Type            *string                `json:"@type"`
+	Id              *string                `json:"id"`
+	DateCreated     time.Time              `json:"dateCreated"`
+	DateModified    time.Time              `json:"dateModified"`
+	JobAssignmentId *string                `json:"jobAssignmentId"`
+	Status          *JobStatus             `json:"status"`
+	Error           *ProblemDetail         `json:"error"`
+	Progress        *float64               `json:"progress"`
+	JobOutput       map[string]interface{} `json:"jobOutput"`
+	ActualStartDate *time.Time             `json:"actualStartDate"`
+	ActualEndDate   *time.Time             `json:"actualEndDate"`
+	ActualDuration  *int64                 `json:"actualDuration"`
+	Custom          map[string]interface{} `json:"custom"`
+}
+
+var JobExecutionType = "JobExecution"
+
+func NewJobExecution(jobAssignmentId string) JobExecution {
+	return JobExecution{
+		Type:            JobExecutionType,
+		JobAssignmentId: jobAssignmentId,
+	}
+}
+
+func (je JobExecution) MarshalJSON() ([]byte, error) {
+	return marshalWithAdditionalProperties(&jobExecutionJson{
+		Type:            &JobExecutionType,
+		Id:              stringPtrOrNull(je.Id),
+		DateCreated:     je.DateCreated,
+		DateModified:    je.DateModified,
+		JobAssignmentId: stringPtrOrNull(je.JobAssignmentId),
+		Status:          jobStatusPtrOrNull(je.Status),
+		Error:           je.Error,
+		Progress:        float64PtrOrNull(je.Progress),
+		JobOutput:       je.JobOutput,
+		ActualStartDate: timePtrOrNull(je.ActualStartDate),
+		ActualEndDate:   timePtrOrNull(je.ActualEndDate),
+		ActualDuration:  int64PtrOrNull(je.ActualDuration),
+		Custom:          je.Custom,
+	}, je.AdditionalProperties)
+}
+
+func (je *JobExecution) UnmarshalJSON(data []byte) error {
+	var tmp jobExecutionJson
+	if err := json.Unmarshal(data, &tmp); err != nil {
+		return err
+	}
+	additionalProperties, err := getAdditionalProperties(data, &tmp)
+	if err != nil {
+		return err
+	}
+
+	je.Type = JobExecutionType
+	je.Id = stringOrEmpty(tmp.Id)
+	je.DateCreated = tmp.DateCreated
+	je.DateModified = tmp.DateModified
+	je.JobAssignmentId = stringOrEmpty(tmp.JobAssignmentId)
+	je.Status = jobStatusOrEmpty(tmp.Status)
+	je.Error = tmp.Error
+	je.Progress = float64OrZero(tmp.Progress)
+	je.JobOutput = tmp.JobOutput
+	je.ActualStartDate = timeOrZero(tmp.ActualStartDate)
+	je.ActualEndDate = timeOrZero(tmp.ActualEndDate)
+	je.ActualDuration = int64OrZero(tmp.ActualDuration)
+	je.Custom = tmp.Custom
+	je.AdditionalProperties = additionalProperties
+
+	return nil
+}
@@ -3,8 +3,9 @@ package model
 import "encoding/json"
 
 type JobParameter struct {
-	ParameterName string
-	ParameterType string
+	ParameterName        string
+	ParameterType        string
+	AdditionalProperties map[string]interface{}
 }
 
 type jobParameterJson struct {
@@ -20,10 +21,10 @@ func NewJobParameter(parameterName, parameterType string) JobParameter {
 }
 
 func (jp JobParameter) MarshalJSON() ([]byte, error) {
-	return json.Marshal(&jobParameterJson{
+	return marshalWithAdditionalProperties(&jobParameterJson{
 		ParameterName: stringPtrOrNull(jp.ParameterName),
 		ParameterType: stringPtrOrNull(jp.ParameterType),
-	})
+	}, jp.AdditionalProperties)
 }
 
 func (jp *JobParameter) UnmarshalJSON(data []byte) error {
@@ -31,9 +32,14 @@ func (jp *JobParameter) UnmarshalJSON(data []byte) error {
 	if err := json.Unmarshal(data, &tmp); err != nil {
 		return err
 	}
+	additionalProperties, err := getAdditionalProperties(data, &tmp)
+	if err != nil {
+		return err
+	}
 
 	jp.ParameterName = stringOrEmpty(tmp.ParameterName)
 	jp.ParameterType = stringOrEmpty(tmp.ParameterType)
+	jp.AdditionalProperties = additionalProperties
 
 	return nil
 }
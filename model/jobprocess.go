@@ -0,0 +1,106 @@
+package model
+
+import (
+	"encoding/json"
+	"time"
+)
+
+type JobProcess struct {
+	Type                 string
+	Id                   string
+	DateCreated          time.Time
+	DateModified         time.Time
+	JobId                string
+	JobAssignmentId      string
+	Status               JobStatus
+	Error                *ProblemDetail
+	Progress             float64
+	JobOutput            map[string]interface{}
+	NotificationEndpoint *NotificationEndpoint
+	Tracker              *McmaTracker
+	DateScheduled        time.Time
+	DateStarted          time.Time
+	DateCompleted        time.Time
+	Custom               map[string]interface{}
+	AdditionalProperties map[string]interface{}
+}
+
+type jobProcessJson struct {
+	Type                 *string                `json:"@type"`
+	Id                   *string                `json:"id"`
+	DateCreated          time.Time              `json:"dateCreated"`
+	DateModified         time.Time              `json:"dateModified"`
+	JobId                *string                `json:"jobId"`
+	JobAssignmentId      *string                `json:"jobAssignmentId"`
+	Status               *JobStatus             `json:"status"`
+	Error                *ProblemDetail         `json:"error"`
+	Progress             *float64               `json:"progress"`
+	JobOutput            map[string]interface{} `json:"jobOutput"`
+	NotificationEndpoint *NotificationEndpoint  `json:"notificationEndpoint"`
+	Tracker              *McmaTracker           `json:"tracker"`
+	DateScheduled        *time.Time             `json:"dateScheduled"`
+	DateStarted          *time.Time             `json:"dateStarted"`
+	DateCompleted        *time.Time             `json:"dateCompleted"`
+	Custom               map[string]interface{} `json:"custom"`
+}
+
+var JobProcessType = "JobProcess"
+
+func NewJobProcess(jobId string) JobProcess {
+	return JobProcess{
+		Type:  JobProcessType,
+		JobId: jobId,
+	}
+}
+
+func (jp JobProcess) MarshalJSON() ([]byte, error) {
+	return marshalWithAdditionalProperties(&jobProcessJson{
+		Type:                 &JobProcessType,
+		Id:                   stringPtrOrNull(jp.Id),
+		DateCreated:          jp.DateCreated,
+		DateModified:         jp.DateModified,
+		JobId:                stringPtrOrNull(jp.JobId),
+		JobAssignmentId:      stringPtrOrNull(jp.JobAssignmentId),
+		Status:               jobStatusPtrOrNull(jp.Status),
+		Error:                jp.Error,
+		Progress:             float64PtrOrNull(jp.Progress),
+		JobOutput:            jp.JobOutput,
+		NotificationEndpoint: jp.NotificationEndpoint,
+		Tracker:              jp.Tracker,
+		DateScheduled:        timePtrOrNull(jp.DateScheduled),
+		DateStarted:          timePtrOrNull(jp.DateStarted),
+		DateCompleted:        timePtrOrNull(jp.DateCompleted),
+		Custom:               jp.Custom,
+	}, jp.AdditionalProperties)
+}
+
+func (jp *JobProcess) UnmarshalJSON(data []byte) error {
+	var tmp jobProcessJson
+	if err := json.Unmarshal(data, &tmp); err != nil {
+		return err
+	}
+	additionalProperties, err := getAdditionalProperties(data, &tmp)
+	if err != nil {
+		return err
+	}
+
+	jp.Type = JobProcessType
+	jp.Id = stringOrEmpty(tmp.Id)
+	jp.DateCreated = tmp.DateCreated
+	jp.DateModified = tmp.DateModified
+	jp.JobId = stringOrEmpty(tmp.JobId)
+	jp.JobAssignmentId = stringOrEmpty(tmp.JobAssignmentId)
+	jp.Status = jobStatusOrEmpty(tmp.Status)
+	jp.Error = tmp.Error
+	jp.Progress = float64OrZero(tmp.Progress)
+	jp.JobOutput = tmp.JobOutput
+	jp.NotificationEndpoint = tmp.NotificationEndpoint
+	jp.Tracker = tmp.Tracker
+	jp.DateScheduled = timeOrZero(tmp.DateScheduled)
+	jp.DateStarted = timeOrZero(tmp.DateStarted)
+	jp.DateCompleted = timeOrZero(tmp.DateCompleted)
+	jp.Custom = tmp.Custom
+	jp.AdditionalProperties = additionalProperties
+
+	return nil
+}
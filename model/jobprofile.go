@@ -15,6 +15,7 @@ type JobProfile struct {
 	OutputParameters        []JobParameter
 	OptionalInputParameters []JobParameter
 	Custom                  map[string]interface{}
+	AdditionalProperties    map[string]interface{}
 }
 
 type jobProfileJson struct {
@@ -39,7 +40,7 @@ func NewJobProfile(name string) JobProfile {
 }
 
 func (jp JobProfile) MarshalJSON() ([]byte, error) {
-	return json.Marshal(&jobProfileJson{
+	return marshalWithAdditionalProperties(&jobProfileJson{
 		Type:                    JobProfileType,
 		Id:                      stringPtrOrNull(jp.Id),
 		DateCreated:             jp.DateCreated,
@@ -49,7 +50,7 @@ func (jp JobProfile) MarshalJSON() ([]byte, error) {
 		OutputParameters:        jp.OutputParameters,
 		OptionalInputParameters: jp.OptionalInputParameters,
 		Custom:                  jp.Custom,
-	})
+	}, jp.AdditionalProperties)
 }
 
 func (jp *JobProfile) UnmarshalJSON(data []byte) error {
@@ -57,6 +58,10 @@ func (jp *JobProfile) UnmarshalJSON(data []byte) error {
 	if err := json.Unmarshal(data, &tmp); err != nil {
 		return err
 	}
+	additionalProperties, err := getAdditionalProperties(data, &tmp)
+	if err != nil {
+		return err
+	}
 
 	jp.Type = JobProfileType
 	jp.Id = stringOrEmpty(tmp.Id)
@@ -67,6 +72,7 @@ func (jp *JobProfile) UnmarshalJSON(data []byte) error {
 	jp.OutputParameters = tmp.OutputParameters
 	jp.OptionalInputParameters = tmp.OptionalInputParameters
 	jp.Custom = tmp.Custom
+	jp.AdditionalProperties = additionalProperties
 
 	return nil
 }
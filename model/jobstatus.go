@@ -0,0 +1,113 @@
+package model
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+type JobStatus string
+
+const (
+	JobStatusNew       JobStatus = "New"
+	JobStatusPending   JobStatus = "Pending"
+	JobStatusAssigned  JobStatus = "Assigned"
+	JobStatusQueued    JobStatus = "Queued"
+	JobStatusScheduled JobStatus = "Scheduled"
+	JobStatusRunning   JobStatus = "Running"
+	JobStatusCompleted JobStatus = "Completed"
+	JobStatusFailed    JobStatus = "Failed"
+	JobStatusCanceled  JobStatus = "Canceled"
+)
+
+// the order in which a job normally moves through its statuses, ending in one of the terminal statuses
+var jobStatusOrder = map[JobStatus]int{
+	JobStatusNew:       0,
+	JobStatusPending:   1,
+	JobStatusAssigned:  2,
+	JobStatusQueued:    3,
+	JobStatusScheduled: 4,
+	JobStatusRunning:   5,
+	JobStatusCompleted: 6,
+	JobStatusFailed:    6,
+	JobStatusCanceled:  6,
+}
+
+func ParseJobStatus(s string) (JobStatus, error) {
+	for status := range jobStatusOrder {
+		if strings.EqualFold(string(status), s) {
+			return status, nil
+		}
+	}
+	return JobStatus(s), fmt.Errorf("unknown job status '%s'", s)
+}
+
+func (s JobStatus) IsKnown() bool {
+	_, known := jobStatusOrder[s]
+	return known
+}
+
+func (s JobStatus) IsTerminal() bool {
+	return s == JobStatusCompleted || s == JobStatusFailed || s == JobStatusCanceled
+}
+
+func (s JobStatus) IsActive() bool {
+	return s.IsKnown() && s != JobStatusNew && !s.IsTerminal()
+}
+
+func (s JobStatus) CanTransitionTo(next JobStatus) bool {
+	return ValidateJobStatusTransition(s, next) == nil
+}
+
+func ValidateJobStatusTransition(from, to JobStatus) error {
+	if !from.IsKnown() {
+		return fmt.Errorf("unknown job status '%s'", from)
+	}
+	if !to.IsKnown() {
+		return fmt.Errorf("unknown job status '%s'", to)
+	}
+	if from == to {
+		return nil
+	}
+	// a failed or canceled job can be restarted, which starts it again from the beginning
+	if (from == JobStatusFailed || from == JobStatusCanceled) && (to == JobStatusNew || to == JobStatusPending) {
+		return nil
+	}
+	if from.IsTerminal() {
+		return fmt.Errorf("cannot transition job from terminal status %s to %s", from, to)
+	}
+	if jobStatusOrder[to] < jobStatusOrder[from] {
+		return fmt.Errorf("cannot transition job from status %s back to %s", from, to)
+	}
+	return nil
+}
+
+func (s *JobStatus) UnmarshalJSON(data []byte) error {
+	var tmp string
+	if err := json.Unmarshal(data, &tmp); err != nil {
+		return err
+	}
+
+	// statuses we don't know about are kept as-is so newer servers don't break older clients
+	status, err := ParseJobStatus(tmp)
+	if err != nil {
+		status = JobStatus(tmp)
+	}
+	*s = status
+
+	return nil
+}
+
+func jobStatusPtrOrNull(s JobStatus) *JobStatus {
+	if len(s) == 0 {
+		return nil
+	}
+	return &s
+}
+
+func jobStatusOrEmpty(s *JobStatus) JobStatus {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
@@ -0,0 +1,64 @@
+package model
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestJobStatusUnmarshalJSONIsCaseInsensitive(t *testing.T) {
+	var job Job
+	if err := json.Unmarshal([]byte(`{ "@type": "Job", "status": "COMPLETED" }`), &job); err != nil {
+		t.Fatalf("%v", err)
+	}
+	if job.Status != JobStatusCompleted {
+		t.Errorf("expected %s, got %s", JobStatusCompleted, job.Status)
+	}
+	if !job.Status.IsTerminal() || job.Status.IsActive() {
+		t.Errorf("expected %s to be terminal and not active", job.Status)
+	}
+
+	var status JobStatus
+	if err := json.Unmarshal([]byte(`"Suspended"`), &status); err != nil {
+		t.Fatalf("%v", err)
+	}
+	if status != "Suspended" || status.IsKnown() {
+		t.Errorf("expected unknown status to be preserved, got %s", status)
+	}
+}
+
+func TestValidateJobStatusTransition(t *testing.T) {
+	legal := [][2]JobStatus{
+		{JobStatusNew, JobStatusPending},
+		{JobStatusNew, JobStatusFailed},
+		{JobStatusPending, JobStatusRunning},
+		{JobStatusQueued, JobStatusScheduled},
+		{JobStatusRunning, JobStatusRunning},
+		{JobStatusRunning, JobStatusCompleted},
+		{JobStatusScheduled, JobStatusCanceled},
+		{JobStatusFailed, JobStatusNew},
+		{JobStatusFailed, JobStatusPending},
+		{JobStatusCanceled, JobStatusNew},
+		{JobStatusCanceled, JobStatusPending},
+	}
+	for _, tr := range legal {
+		if err := ValidateJobStatusTransition(tr[0], tr[1]); err != nil {
+			t.Errorf("expected %s -> %s to be legal: %v", tr[0], tr[1], err)
+		}
+	}
+
+	illegal := [][2]JobStatus{
+		{JobStatusRunning, JobStatusPending},
+		{JobStatusCompleted, JobStatusRunning},
+		{JobStatusFailed, JobStatusCompleted},
+		{JobStatusFailed, JobStatusRunning},
+		{JobStatusCanceled, JobStatusQueued},
+		{JobStatusCompleted, JobStatusNew},
+		{JobStatusCompleted, JobStatusPending},
+		{JobStatusNew, "Suspended"},
+	}
+	for _, tr := range illegal {
+		if tr[0].CanTransitionTo(tr[1]) {
+			t.Errorf("expected %s -> %s to be illegal", tr[0], tr[1])
+		}
+	}
+}
@@ -0,0 +1,88 @@
+package model
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+var jsonPropertyNamesCache sync.Map
+
+func getJsonPropertyNames(t reflect.Type) map[string]struct{} {
+	if cached, found := jsonPropertyNamesCache.Load(t); found {
+		return cached.(map[string]struct{})
+	}
+	names := make(map[string]struct{})
+	for i := 0; i < t.NumField(); i++ {
+		tag := t.Field(i).Tag.Get("json")
+		if tag == "" || tag == "-" {
+			continue
+		}
+		names[strings.Split(tag, ",")[0]] = struct{}{}
+	}
+	jsonPropertyNamesCache.Store(t, names)
+	return names
+}
+
+// marshalWithAdditionalProperties marshals the shadow struct v and adds any properties that were not recognized
+// when the object was unmarshalled, so that fields added by newer servers survive a round trip through this client
+func marshalWithAdditionalProperties(v interface{}, additionalProperties map[string]interface{}) ([]byte, error) {
+	data, err := json.Marshal(v)
+	if err != nil || len(additionalProperties) == 0 {
+		return data, err
+	}
+
+	var m map[string]json.RawMessage
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, err
+	}
+	for key, value := range additionalProperties {
+		if hasPropertyName(m, key) {
+			continue
+		}
+		valueJson, err := json.Marshal(value)
+		if err != nil {
+			return nil, err
+		}
+		m[key] = valueJson
+	}
+	return json.Marshal(m)
+}
+
+// getAdditionalProperties returns the top-level properties in data that have no matching field on the shadow
+// struct v, or nil if there are none
+func getAdditionalProperties(data []byte, v interface{}) (map[string]interface{}, error) {
+	var m map[string]interface{}
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, err
+	}
+	t := reflect.TypeOf(v)
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	names := getJsonPropertyNames(t)
+	for key := range m {
+		if hasPropertyName(names, key) {
+			delete(m, key)
+		}
+	}
+	if len(m) == 0 {
+		return nil, nil
+	}
+	return m, nil
+}
+
+// hasPropertyName returns whether names has a key matching name case-insensitively, which is how encoding/json
+// matches object keys to struct fields
+func hasPropertyName[V any](names map[string]V, name string) bool {
+	if _, found := names[name]; found {
+		return true
+	}
+	for key := range names {
+		if strings.EqualFold(key, name) {
+			return true
+		}
+	}
+	return false
+}
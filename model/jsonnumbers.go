@@ -0,0 +1,29 @@
+package model
+
+func float64PtrOrNull(f float64) *float64 {
+	if f == 0 {
+		return nil
+	}
+	return &f
+}
+
+func float64OrZero(f *float64) float64 {
+	if f == nil {
+		return 0
+	}
+	return *f
+}
+
+func int64PtrOrNull(i int64) *int64 {
+	if i == 0 {
+		return nil
+	}
+	return &i
+}
+
+func int64OrZero(i *int64) int64 {
+	if i == nil {
+		return 0
+	}
+	return *i
+}
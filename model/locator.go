@@ -5,8 +5,9 @@ import "encoding/json"
 var LocatorType = "Locator"
 
 type Locator struct {
-	Type string
-	Url  string
+	Type                 string
+	Url                  string
+	AdditionalProperties map[string]interface{}
 }
 
 type locatorJson struct {
@@ -22,10 +23,10 @@ func NewLocator(url string) Locator {
 }
 
 func (l Locator) MarshalJSON() ([]byte, error) {
-	return json.Marshal(&locatorJson{
+	return marshalWithAdditionalProperties(&locatorJson{
 		Type: &LocatorType,
 		Url:  stringPtrOrNull(l.Url),
-	})
+	}, l.AdditionalProperties)
 }
 
 func (l *Locator) UnmarshalJSON(data []byte) error {
@@ -33,9 +34,14 @@ func (l *Locator) UnmarshalJSON(data []byte) error {
 	if err := json.Unmarshal(data, &tmp); err != nil {
 		return err
 	}
+	additionalProperties, err := getAdditionalProperties(data, &tmp)
+	if err != nil {
+		return err
+	}
 
 	l.Type = LocatorType
 	l.Url = stringOrEmpty(tmp.Url)
+	l.AdditionalProperties = additionalProperties
 
 	return nil
 }
@@ -3,10 +3,11 @@ package model
 import "encoding/json"
 
 type McmaTracker struct {
-	Type   string
-	Id     string
-	Label  string
-	Custom map[string]string
+	Type                 string
+	Id                   string
+	Label                string
+	Custom               map[string]string
+	AdditionalProperties map[string]interface{}
 }
 
 type mcmaTrackerJson struct {
@@ -28,12 +29,12 @@ func NewTracker(id, label string, custom map[string]string) McmaTracker {
 }
 
 func (t McmaTracker) MarshalJSON() ([]byte, error) {
-	return json.Marshal(&mcmaTrackerJson{
+	return marshalWithAdditionalProperties(&mcmaTrackerJson{
 		Type:   &McmaTrackerType,
 		Id:     stringPtrOrNull(t.Id),
 		Label:  stringPtrOrNull(t.Label),
 		Custom: t.Custom,
-	})
+	}, t.AdditionalProperties)
 }
 
 func (t *McmaTracker) UnmarshalJSON(data []byte) error {
@@ -41,11 +42,16 @@ func (t *McmaTracker) UnmarshalJSON(data []byte) error {
 	if err := json.Unmarshal(data, &tmp); err != nil {
 		return err
 	}
+	additionalProperties, err := getAdditionalProperties(data, &tmp)
+	if err != nil {
+		return err
+	}
 
 	t.Type = McmaTrackerType
 	t.Id = stringOrEmpty(tmp.Id)
 	t.Label = stringOrEmpty(tmp.Label)
 	t.Custom = tmp.Custom
+	t.AdditionalProperties = additionalProperties
 
 	return nil
 }
@@ -3,10 +3,11 @@ package model
 import "encoding/json"
 
 type Notification struct {
-	Type    string
-	Source  string
-	Content interface{}
-	Custom  map[string]interface{}
+	Type                 string
+	Source               string
+	Content              interface{}
+	Custom               map[string]interface{}
+	AdditionalProperties map[string]interface{}
 }
 
 type notificationJson struct {
@@ -27,11 +28,12 @@ func NewNotification(source string, content interface{}) Notification {
 }
 
 func (n Notification) MarshalJSON() ([]byte, error) {
-	return json.Marshal(&notificationJson{
+	return marshalWithAdditionalProperties(&notificationJson{
 		Type:    &NotificationType,
 		Source:  stringPtrOrNull(n.Source),
 		Content: n.Content,
-	})
+		Custom:  n.Custom,
+	}, n.AdditionalProperties)
 }
 
 func (n *Notification) UnmarshalJSON(data []byte) error {
@@ -39,10 +41,16 @@ func (n *Notification) UnmarshalJSON(data []byte) error {
 	if err := json.Unmarshal(data, &tmp); err != nil {
 		return err
 	}
+	additionalProperties, err := getAdditionalProperties(data, &tmp)
+	if err != nil {
+		return err
+	}
 
 	n.Type = NotificationType
 	n.Source = stringOrEmpty(tmp.Source)
 	n.Content = tmp.Content
+	n.Custom = tmp.Custom
+	n.AdditionalProperties = additionalProperties
 
 	return nil
 }
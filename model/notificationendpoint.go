@@ -3,10 +3,11 @@ package model
 import "encoding/json"
 
 type NotificationEndpoint struct {
-	Type         string
-	Id           string
-	HttpEndpoint string
-	Custom       map[string]interface{}
+	Type                 string
+	Id                   string
+	HttpEndpoint         string
+	Custom               map[string]interface{}
+	AdditionalProperties map[string]interface{}
 }
 
 type notificationEndpointJson struct {
@@ -27,11 +28,12 @@ func NewNotificationEndpoint(id, httpEndpoint string) NotificationEndpoint {
 }
 
 func (ne NotificationEndpoint) MarshalJSON() ([]byte, error) {
-	return json.Marshal(&notificationEndpointJson{
+	return marshalWithAdditionalProperties(&notificationEndpointJson{
 		Type:         &NotificationEndpointType,
 		Id:           stringPtrOrNull(ne.Id),
 		HttpEndpoint: stringPtrOrNull(ne.HttpEndpoint),
-	})
+		Custom:       ne.Custom,
+	}, ne.AdditionalProperties)
 }
 
 func (ne *NotificationEndpoint) UnmarshalJSON(data []byte) error {
@@ -39,10 +41,16 @@ func (ne *NotificationEndpoint) UnmarshalJSON(data []byte) error {
 	if err := json.Unmarshal(data, &tmp); err != nil {
 		return err
 	}
+	additionalProperties, err := getAdditionalProperties(data, &tmp)
+	if err != nil {
+		return err
+	}
 
 	ne.Type = NotificationEndpointType
 	ne.Id = stringOrEmpty(tmp.Id)
 	ne.HttpEndpoint = stringOrEmpty(tmp.HttpEndpoint)
+	ne.Custom = tmp.Custom
+	ne.AdditionalProperties = additionalProperties
 
 	return nil
 }
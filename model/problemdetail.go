@@ -0,0 +1,74 @@
+package model
+
+import "encoding/json"
+
+// ProblemDetail is an RFC 7807 problem details object. The problem type URI is held in ProblemType, as Type is
+// used for the MCMA @type discriminator on every model. Extension members are collected in Custom.
+type ProblemDetail struct {
+	Type        string
+	ProblemType string
+	Title       string
+	Status      int
+	Detail      string
+	Instance    string
+	Custom      map[string]interface{}
+}
+
+type problemDetailJson struct {
+	Type        *string `json:"@type"`
+	ProblemType *string `json:"type"`
+	Title       *string `json:"title"`
+	Status      *int    `json:"status,omitempty"`
+	Detail      *string `json:"detail"`
+	Instance    *string `json:"instance"`
+}
+
+var ProblemDetailType = "ProblemDetail"
+
+func NewProblemDetail(problemType, title, detail string) ProblemDetail {
+	return ProblemDetail{
+		Type:        ProblemDetailType,
+		ProblemType: problemType,
+		Title:       title,
+		Detail:      detail,
+	}
+}
+
+func (pd ProblemDetail) MarshalJSON() ([]byte, error) {
+	var status *int
+	if pd.Status != 0 {
+		status = &pd.Status
+	}
+	return marshalWithAdditionalProperties(&problemDetailJson{
+		Type:        &ProblemDetailType,
+		ProblemType: stringPtrOrNull(pd.ProblemType),
+		Title:       stringPtrOrNull(pd.Title),
+		Status:      status,
+		Detail:      stringPtrOrNull(pd.Detail),
+		Instance:    stringPtrOrNull(pd.Instance),
+	}, pd.Custom)
+}
+
+func (pd *ProblemDetail) UnmarshalJSON(data []byte) error {
+	var tmp problemDetailJson
+	if err := json.Unmarshal(data, &tmp); err != nil {
+		return err
+	}
+	extensionMembers, err := getAdditionalProperties(data, &tmp)
+	if err != nil {
+		return err
+	}
+
+	pd.Type = ProblemDetailType
+	pd.ProblemType = stringOrEmpty(tmp.ProblemType)
+	pd.Title = stringOrEmpty(tmp.Title)
+	pd.Status = 0
+	if tmp.Status != nil {
+		pd.Status = *tmp.Status
+	}
+	pd.Detail = stringOrEmpty(tmp.Detail)
+	pd.Instance = stringOrEmpty(tmp.Instance)
+	pd.Custom = extensionMembers
+
+	return nil
+}
@@ -0,0 +1,43 @@
+package model
+
+type QAJob struct {
+	Job
+}
+
+var QAJobType = "QAJob"
+
+func NewQAJob(jobProfileId string, jobInput map[string]interface{}) QAJob {
+	return QAJob{
+		Job: Job{
+			Type:         QAJobType,
+			JobProfileId: jobProfileId,
+			JobInput:     jobInput,
+		},
+	}
+}
+
+func NewQAJobWithNotificationEndpoint(jobProfileId string, jobInput map[string]interface{}, notificationEndpoint NotificationEndpoint) QAJob {
+	return QAJob{
+		Job: Job{
+			Type:                 QAJobType,
+			JobProfileId:         jobProfileId,
+			JobInput:             jobInput,
+			NotificationEndpoint: &notificationEndpoint,
+		},
+	}
+}
+
+func (j QAJob) MarshalJSON() ([]byte, error) {
+	j.Type = QAJobType
+	return j.Job.MarshalJSON()
+}
+
+func (j *QAJob) UnmarshalJSON(data []byte) error {
+	if err := j.Job.UnmarshalJSON(data); err != nil {
+		return err
+	}
+
+	j.Type = QAJobType
+
+	return nil
+}
@@ -6,8 +6,9 @@ import (
 )
 
 type QueryResults struct {
-	Results            []interface{}
-	NextPageStartToken string
+	Results              []interface{}
+	NextPageStartToken   string
+	AdditionalProperties map[string]interface{}
 }
 
 type queryResultsJson struct {
@@ -35,10 +36,10 @@ func (qr QueryResults) GetResults(t reflect.Type) ([]interface{}, error) {
 }
 
 func (qr QueryResults) MarshalJSON() ([]byte, error) {
-	return json.Marshal(&queryResultsJson{
+	return marshalWithAdditionalProperties(&queryResultsJson{
 		Results:            qr.Results,
 		NextPageStartToken: stringPtrOrNull(qr.NextPageStartToken),
-	})
+	}, qr.AdditionalProperties)
 }
 
 func (qr *QueryResults) UnmarshalJSON(data []byte) error {
@@ -46,9 +47,14 @@ func (qr *QueryResults) UnmarshalJSON(data []byte) error {
 	if err := json.Unmarshal(data, &tmp); err != nil {
 		return err
 	}
+	additionalProperties, err := getAdditionalProperties(data, &tmp)
+	if err != nil {
+		return err
+	}
 
 	qr.Results = tmp.Results
 	qr.NextPageStartToken = stringOrEmpty(tmp.NextPageStartToken)
+	qr.AdditionalProperties = additionalProperties
 
 	return nil
 }
@@ -0,0 +1,31 @@
+package model
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestQueryResultsRoundTripKeepsAdditionalProperties(t *testing.T) {
+	var qr QueryResults
+	if err := json.Unmarshal([]byte(`{ "results": [], "nextPageStartToken": "next", "totalCount": 5 }`), &qr); err != nil {
+		t.Fatalf("%v", err)
+	}
+	if qr.NextPageStartToken != "next" || qr.AdditionalProperties["totalCount"] != float64(5) {
+		t.Errorf("unexpected query results %+v", qr)
+	}
+	if _, found := qr.AdditionalProperties["results"]; found {
+		t.Errorf("did not expect known properties in additional properties")
+	}
+
+	data, err := json.Marshal(qr)
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	var m map[string]interface{}
+	if err := json.Unmarshal(data, &m); err != nil {
+		t.Fatalf("%v", err)
+	}
+	if m["totalCount"] != float64(5) || m["nextPageStartToken"] != "next" {
+		t.Errorf("expected the additional properties to be written back, got %v", m)
+	}
+}
@@ -3,10 +3,11 @@ package model
 import "encoding/json"
 
 type ResourceEndpoint struct {
-	Type         string
-	ResourceType string
-	HttpEndpoint string
-	AuthType     string
+	Type                 string
+	ResourceType         string
+	HttpEndpoint         string
+	AuthType             string
+	AdditionalProperties map[string]interface{}
 }
 
 type resourceEndpointJson struct {
@@ -36,12 +37,12 @@ func NewResourceEndpointWithAuth(resourceType, httpEndpoint, authType string) Re
 }
 
 func (re ResourceEndpoint) MarshalJSON() ([]byte, error) {
-	return json.Marshal(&resourceEndpointJson{
+	return marshalWithAdditionalProperties(&resourceEndpointJson{
 		Type:         &ResourceEndpointType,
 		ResourceType: stringPtrOrNull(re.ResourceType),
 		HttpEndpoint: stringPtrOrNull(re.HttpEndpoint),
 		AuthType:     stringPtrOrNull(re.AuthType),
-	})
+	}, re.AdditionalProperties)
 }
 
 func (re *ResourceEndpoint) UnmarshalJSON(data []byte) error {
@@ -49,11 +50,16 @@ func (re *ResourceEndpoint) UnmarshalJSON(data []byte) error {
 	if err := json.Unmarshal(data, &tmp); err != nil {
 		return err
 	}
+	additionalProperties, err := getAdditionalProperties(data, &tmp)
+	if err != nil {
+		return err
+	}
 
 	re.Type = ResourceEndpointType
 	re.ResourceType = stringOrEmpty(tmp.ResourceType)
 	re.HttpEndpoint = stringOrEmpty(tmp.HttpEndpoint)
 	re.AuthType = stringOrEmpty(tmp.AuthType)
+	re.AdditionalProperties = additionalProperties
 
 	return nil
 }
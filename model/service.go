@@ -6,18 +6,19 @@ import (
 )
 
 type Service struct {
-	Type            string
-	Id              string
-	DateCreated     time.Time
-	DateModified    time.Time
-	Name            string
-	AuthType        string
-	Resources       []ResourceEndpoint
-	JobType         string
-	JobProfileIds   []string
-	InputLocations  []Locator
-	OutputLocations []Locator
-	Custom          map[string]interface{}
+	Type                 string
+	Id                   string
+	DateCreated          time.Time
+	DateModified         time.Time
+	Name                 string
+	AuthType             string
+	Resources            []ResourceEndpoint
+	JobType              string
+	JobProfileIds        []string
+	InputLocations       []Locator
+	OutputLocations      []Locator
+	Custom               map[string]interface{}
+	AdditionalProperties map[string]interface{}
 }
 
 type serviceJson struct {
@@ -89,7 +90,7 @@ func NewServiceForJobTypeWithLocations(name, authType string, resources []Resour
 }
 
 func (s Service) MarshalJSON() ([]byte, error) {
-	return json.Marshal(&serviceJson{
+	return marshalWithAdditionalProperties(&serviceJson{
 		Type:            &ServiceType,
 		Id:              stringPtrOrNull(s.Id),
 		DateCreated:     s.DateCreated,
@@ -101,7 +102,8 @@ func (s Service) MarshalJSON() ([]byte, error) {
 		JobProfileIds:   s.JobProfileIds,
 		InputLocations:  s.InputLocations,
 		OutputLocations: s.OutputLocations,
-	})
+		Custom:          s.Custom,
+	}, s.AdditionalProperties)
 }
 
 func (s *Service) UnmarshalJSON(data []byte) error {
@@ -109,6 +111,10 @@ func (s *Service) UnmarshalJSON(data []byte) error {
 	if err := json.Unmarshal(data, &tmp); err != nil {
 		return err
 	}
+	additionalProperties, err := getAdditionalProperties(data, &tmp)
+	if err != nil {
+		return err
+	}
 
 	s.Type = ServiceType
 	s.Id = stringOrEmpty(tmp.Id)
@@ -121,6 +127,8 @@ func (s *Service) UnmarshalJSON(data []byte) error {
 	s.JobProfileIds = tmp.JobProfileIds
 	s.InputLocations = tmp.InputLocations
 	s.OutputLocations = tmp.OutputLocations
+	s.Custom = tmp.Custom
+	s.AdditionalProperties = additionalProperties
 
 	return nil
 }
@@ -26,3 +26,100 @@ func TestServiceUnmarshalJSON(t *testing.T) {
 	t.Logf("Id = %s", s.Id)
 	t.Logf("Name = %s", s.Name)
 }
+
+func TestServiceRoundTripKeepsCustomAndAdditionalProperties(t *testing.T) {
+	j := `{
+		"@type": "Service",
+		"id": "https://service-registry/api/services/1",
+		"name": "test",
+		"custom": { "owner": "team-a", "limits": { "maxJobs": 4 } },
+		"newServerField": "value",
+		"newServerObject": { "enabled": true }
+	}`
+	var s Service
+	if err := json.Unmarshal([]byte(j), &s); err != nil {
+		t.Fatalf("%v", err)
+	}
+	if s.Custom["owner"] != "team-a" {
+		t.Errorf("expected custom to be read, got %v", s.Custom)
+	}
+	if s.AdditionalProperties["newServerField"] != "value" {
+		t.Errorf("expected unknown properties to be captured, got %v", s.AdditionalProperties)
+	}
+	if _, found := s.AdditionalProperties["custom"]; found {
+		t.Errorf("did not expect known properties in additional properties")
+	}
+
+	data, err := json.Marshal(s)
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	var m map[string]interface{}
+	if err := json.Unmarshal(data, &m); err != nil {
+		t.Fatalf("%v", err)
+	}
+	custom, _ := m["custom"].(map[string]interface{})
+	if custom["owner"] != "team-a" || custom["limits"] == nil {
+		t.Errorf("expected custom to be written, got %v", m["custom"])
+	}
+	if m["newServerField"] != "value" || m["newServerObject"] == nil {
+		t.Errorf("expected unknown properties to be written, got %v", m)
+	}
+}
+
+func TestNotificationRoundTripKeepsCustom(t *testing.T) {
+	n := NewNotification("https://job-processor/api/jobs/1", map[string]interface{}{"status": "Completed"})
+	n.Custom = map[string]interface{}{"attempt": 2.0}
+	ne := NewNotificationEndpoint("1", "https://client/notifications")
+	ne.Custom = map[string]interface{}{"secret": "abc"}
+
+	data, err := json.Marshal(n)
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	var outN Notification
+	if err := json.Unmarshal(data, &outN); err != nil {
+		t.Fatalf("%v", err)
+	}
+	if outN.Custom["attempt"] != 2.0 {
+		t.Errorf("expected notification custom to round trip, got %v", outN.Custom)
+	}
+
+	data, err = json.Marshal(ne)
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	var outNe NotificationEndpoint
+	if err := json.Unmarshal(data, &outNe); err != nil {
+		t.Fatalf("%v", err)
+	}
+	if outNe.Custom["secret"] != "abc" {
+		t.Errorf("expected notification endpoint custom to round trip, got %v", outNe.Custom)
+	}
+}
+
+func TestServiceKnownPropertiesMatchCaseInsensitively(t *testing.T) {
+	var s Service
+	if err := json.Unmarshal([]byte(`{ "@type": "Service", "Name": "test", "JOBTYPE": "AmeJob" }`), &s); err != nil {
+		t.Fatalf("%v", err)
+	}
+	if s.Name != "test" || s.JobType != "AmeJob" {
+		t.Errorf("expected the properties to be read into their fields, got %+v", s)
+	}
+	if len(s.AdditionalProperties) != 0 {
+		t.Errorf("did not expect known properties in additional properties, got %v", s.AdditionalProperties)
+	}
+
+	s.AdditionalProperties = map[string]interface{}{"NAME": "other"}
+	data, err := json.Marshal(s)
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	var m map[string]interface{}
+	if err := json.Unmarshal(data, &m); err != nil {
+		t.Fatalf("%v", err)
+	}
+	if _, found := m["NAME"]; found || m["name"] != "test" {
+		t.Errorf("expected an additional property matching a known property to be dropped, got %v", m)
+	}
+}
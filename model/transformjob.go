@@ -0,0 +1,43 @@
+package model
+
+type TransformJob struct {
+	Job
+}
+
+var TransformJobType = "TransformJob"
+
+func NewTransformJob(jobProfileId string, jobInput map[string]interface{}) TransformJob {
+	return TransformJob{
+		Job: Job{
+			Type:         TransformJobType,
+			JobProfileId: jobProfileId,
+			JobInput:     jobInput,
+		},
+	}
+}
+
+func NewTransformJobWithNotificationEndpoint(jobProfileId string, jobInput map[string]interface{}, notificationEndpoint NotificationEndpoint) TransformJob {
+	return TransformJob{
+		Job: Job{
+			Type:                 TransformJobType,
+			JobProfileId:         jobProfileId,
+			JobInput:             jobInput,
+			NotificationEndpoint: &notificationEndpoint,
+		},
+	}
+}
+
+func (j TransformJob) MarshalJSON() ([]byte, error) {
+	j.Type = TransformJobType
+	return j.Job.MarshalJSON()
+}
+
+func (j *TransformJob) UnmarshalJSON(data []byte) error {
+	if err := j.Job.UnmarshalJSON(data); err != nil {
+		return err
+	}
+
+	j.Type = TransformJobType
+
+	return nil
+}
@@ -0,0 +1,43 @@
+package model
+
+type WorkflowJob struct {
+	Job
+}
+
+var WorkflowJobType = "WorkflowJob"
+
+func NewWorkflowJob(jobProfileId string, jobInput map[string]interface{}) WorkflowJob {
+	return WorkflowJob{
+		Job: Job{
+			Type:         WorkflowJobType,
+			JobProfileId: jobProfileId,
+			JobInput:     jobInput,
+		},
+	}
+}
+
+func NewWorkflowJobWithNotificationEndpoint(jobProfileId string, jobInput map[string]interface{}, notificationEndpoint NotificationEndpoint) WorkflowJob {
+	return WorkflowJob{
+		Job: Job{
+			Type:                 WorkflowJobType,
+			JobProfileId:         jobProfileId,
+			JobInput:             jobInput,
+			NotificationEndpoint: &notificationEndpoint,
+		},
+	}
+}
+
+func (j WorkflowJob) MarshalJSON() ([]byte, error) {
+	j.Type = WorkflowJobType
+	return j.Job.MarshalJSON()
+}
+
+func (j *WorkflowJob) UnmarshalJSON(data []byte) error {
+	if err := j.Job.UnmarshalJSON(data); err != nil {
+		return err
+	}
+
+	j.Type = WorkflowJobType
+
+	return nil
+}